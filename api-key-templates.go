@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// apiKeyTable is the table storing hashed API keys when the api_keys auth mode is enabled
+const apiKeyTable = "api_keys"
+
+// generateAPIKeyFile renders one of the API key templates, which only need the module name
+func generateAPIKeyFile(templateName, moduleName string) string {
+	variables := map[string]string{
+		"module_name": moduleName,
+	}
+
+	content, err := processTemplate(templateName, variables)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to generate %s: %v", templateName, err))
+	}
+	return content
+}
+
+// generateAPIKeyTable creates the api_keys table of the goose migration
+func generateAPIKeyTable(schema string) string {
+	tableName := apiKeyTable
+	if schema != "" {
+		tableName = fmt.Sprintf("%s.%s", schema, apiKeyTable)
+	}
+
+	content, err := processTemplate("goose-api-keys", map[string]string{"table_name": tableName})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to generate api_keys table: %v", err))
+	}
+	return content
+}
+
+// generateAPIKeyAuth creates the API key model, verifier, repository, middleware and admin CLI
+func generateAPIKeyAuth(moduleName string) error {
+	files := map[string]string{
+		filepath.Join(moduleName, "internal", "domain", "model", "api_key.go"):                             generateAPIKeyFile("api-key-model", moduleName),
+		filepath.Join(moduleName, "internal", "auth", "apikey.go"):                                         generateAPIKeyFile("auth-api-key", moduleName),
+		filepath.Join(moduleName, "internal", "auth", "apikey_test.go"):                                    generateAPIKeyFile("auth-api-key-test", moduleName),
+		filepath.Join(moduleName, "internal", "repository", "implementor", "postgres", "api_keys_repo.go"): generateAPIKeyFile("postgres-api-key-repository", moduleName),
+		filepath.Join(moduleName, "internal", "interactor", "rest", "rest_api_key.go"):                     generateAPIKeyFile("rest-api-key", moduleName),
+		filepath.Join(moduleName, "cmd", "apikey", "main.go"):                                              generateAPIKeyFile("api-key-cli", moduleName),
+	}
+
+	for filePath, content := range files {
+		if err := writeFile(filePath, content); err != nil {
+			return err
+		}
+		fmt.Printf("Created: %s\n", filePath)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// auditLogTable is the table recording the changes of every record when the audit log is enabled
+const auditLogTable = "audit_log"
+
+// generateAuditFile renders one of the audit templates, which only need the module name
+func generateAuditFile(templateName, moduleName string) string {
+	variables := map[string]string{
+		"module_name": moduleName,
+	}
+
+	content, err := processTemplate(templateName, variables)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to generate %s: %v", templateName, err))
+	}
+	return content
+}
+
+// generateAuditLogTable creates the audit_log table of the goose migration
+func generateAuditLogTable(schema string) string {
+	tableName := auditLogTable
+	if schema != "" {
+		tableName = fmt.Sprintf("%s.%s", schema, auditLogTable)
+	}
+
+	content, err := processTemplate("goose-audit-log", map[string]string{"table_name": tableName})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to generate audit_log table: %v", err))
+	}
+	return content
+}
+
+// generateAudit creates the audit column model and the audit log model, repository, diffing and DTOs
+func generateAudit(moduleName string) error {
+	files := map[string]string{}
+	if usesAuditColumns() {
+		files[filepath.Join(moduleName, "internal", "domain", "model", "audit_field.go")] = generateAuditFile("audit-field", moduleName)
+	}
+	if usesAuditLog() {
+		files[filepath.Join(moduleName, "internal", "domain", "model", "audit_entry.go")] = generateAuditFile("audit-entry-model", moduleName)
+		// Named so that no table's DTO (<entity>.go) or service (<entity>_service.go) can take their files
+		files[filepath.Join(moduleName, "internal", "application", "audit_log.go")] = generateAuditFile("application-audit", moduleName)
+		files[filepath.Join(moduleName, "internal", "application", "audit_log_test.go")] = generateAuditFile("application-audit-test", moduleName)
+		files[filepath.Join(moduleName, "internal", "application", "dto", "audit_log.go")] = generateAuditFile("dto-audit", moduleName)
+		files[filepath.Join(moduleName, "internal", "repository", "implementor", "postgres", "audit_log_repo.go")] = generateAuditFile("postgres-audit-log-repository", moduleName)
+	}
+
+	for filePath, content := range files {
+		if err := writeFile(filePath, content); err != nil {
+			return err
+		}
+		fmt.Printf("Created: %s\n", filePath)
+	}
+	return nil
+}
@@ -8,7 +8,15 @@ import (
 // generateGoMod creates go.mod content using templates
 func generateGoMod(moduleName string) string {
 	variables := map[string]string{
-		"module_name": moduleName,
+		"module_name":      moduleName,
+		"wrapper_requires": "",
+		"graphql_requires": "",
+	}
+	if !usesProblemDetails() {
+		variables["wrapper_requires"] = "\n\tgithub.com/RizkiAnurka/go-library v1.0.4"
+	}
+	if usesGraphQL() {
+		variables["graphql_requires"] = "\n\tgithub.com/graph-gophers/graphql-go v1.5.0"
 	}
 
 	content, err := processTemplate("go-mod", variables)
@@ -34,27 +42,38 @@ func generateMainGo(moduleName string, tables []Table) string {
 	var additionalImports strings.Builder
 
 	additionalImports.WriteString(fmt.Sprintf("\n\t\"%s/internal/application\"", moduleName))
+	additionalImports.WriteString(fmt.Sprintf("\n\t\"%s/internal/auth\"", moduleName))
 	additionalImports.WriteString(fmt.Sprintf("\n\t\"%s/internal/interactor\"", moduleName))
 	additionalImports.WriteString(fmt.Sprintf("\n\t\"%s/internal/interactor/rest\"", moduleName))
+	additionalImports.WriteString(fmt.Sprintf("\n\tgrpcapi \"%s/internal/interactor/grpc\"", moduleName))
 	additionalImports.WriteString(fmt.Sprintf("\n\t\"%s/internal/repository/implementor/postgres\"", moduleName))
 
 	repoInit.WriteString("\n\t// Initialize repositories\n")
 	appServiceInit.WriteString("\n\t// Initialize application services\n")
 	adapterInit.WriteString("\n\t// Initialize interactor adapters\n")
 
+	// Services share the transactor, and audited services the audit log
+	repoInit.WriteString("\ttransactor := postgres.NewTransactor(db)\n")
+	serviceArguments := ", transactor"
+	if usesAuditLog() {
+		repoInit.WriteString("\tauditLogRepo := postgres.NewAuditLogRepo(db, env.DBQueryTimeout)\n")
+		serviceArguments += ", auditLogRepo"
+	}
+
 	for i, table := range tables {
-		structName := toCamelCase(strings.TrimSuffix(table.Name, "s"))
-		entityName := strings.ToLower(structName)
-		entityPlural := strings.ToLower(table.Name)
+		names := namesFor(table)
+		structName := names.Struct
+		entityName := names.Entity
+		entityPlural := names.EntityPlural
 
 		// Repository initialization
-		repoInit.WriteString(fmt.Sprintf("\t%sRepo := postgres.New%sRepo(db)\n", entityName, structName))
+		repoInit.WriteString(fmt.Sprintf("\t%sRepo := postgres.New%sRepo(db, env.DBQueryTimeout)\n", entityName, structName))
 
 		// Application service initialization
-		appServiceInit.WriteString(fmt.Sprintf("\t%sAppService := application.New%sDomain(ctx, %sRepo)\n", entityName, structName, entityName))
+		appServiceInit.WriteString(fmt.Sprintf("\t%sAppService := application.New%sDomain(%sRepo%s)\n", entityName, structName, entityName, serviceArguments))
 
 		// Adapter initialization
-		adapterInit.WriteString(fmt.Sprintf("\t%sAdapter := interactor.New%sAdapter(ctx, %sAppService)\n", entityName, structName, entityName))
+		adapterInit.WriteString(fmt.Sprintf("\t%sAdapter := interactor.New%sAdapter(%sAppService)\n", entityName, structName, entityName))
 
 		// Service parameters for REST API
 		if i > 0 {
@@ -64,17 +83,40 @@ func generateMainGo(moduleName string, tables []Table) string {
 
 		// Log endpoints
 		endpoints.WriteString(fmt.Sprintf("\n\tlog.Info(\"  GET/POST /%s - %s management\")", entityPlural, structName))
-		endpoints.WriteString(fmt.Sprintf("\n\tlog.Info(\"  GET/PUT/DELETE /%s/{id} - %s operations\")", entityPlural, structName))
+		endpoints.WriteString(fmt.Sprintf("\n\tlog.Info(\"  GET/PUT/PATCH/DELETE /%s/{id} - %s operations\")", entityPlural, structName))
+		endpoints.WriteString(fmt.Sprintf("\n\tlog.Info(\"  POST /%s:batchCreate, :batchUpsert, :batchDelete - %s bulk operations\")", entityPlural, structName))
+		endpoints.WriteString(fmt.Sprintf("\n\tlog.Info(\"  GET /%s/export, POST /%s/import - %s CSV and NDJSON transfer\")", entityPlural, entityPlural, structName))
+		if usesAuditLog() {
+			endpoints.WriteString(fmt.Sprintf("\n\tlog.Info(\"  GET /%s/{id}/history - %s audit log\")", entityPlural, structName))
+		}
+		if usesSoftDelete(table) {
+			endpoints.WriteString(fmt.Sprintf("\n\tlog.Info(\"  POST /%s/{id}/restore, DELETE /%s/{id}/purge - %s recovery\")", entityPlural, entityPlural, structName))
+		}
 	}
 
 	variables := map[string]string{
 		"module_name":                        moduleName,
+		"query_timeout":                      queryTimeout(),
+		"tenant_claim":                       tenantClaim(),
 		"additional_imports":                 additionalImports.String(),
 		"repository_initialization":          repoInit.String(),
 		"application_service_initialization": appServiceInit.String(),
 		"adapter_initialization":             adapterInit.String(),
-		"service_parameters":                 ", " + serviceParams.String(),
+		"service_parameters":                 serviceParams.String(),
 		"endpoint_logging":                   endpoints.String(),
+		"auth_setup":                         "",
+		"auth_arguments":                     "",
+		"graphql_routes":                     "",
+	}
+	if usesAPIKeys() {
+		variables["auth_setup"] = "\n\tapiKeys := auth.NewAPIKeyVerifier(postgres.NewAPIKeyRepo(db, env.DBQueryTimeout))"
+		variables["auth_arguments"] = "apiKeys, "
+	}
+	if usesGraphQL() {
+		additionalImports.WriteString(fmt.Sprintf("\n\tgraphqlapi \"%s/internal/interactor/graphql\"", moduleName))
+		variables["additional_imports"] = additionalImports.String()
+		variables["graphql_routes"] = fmt.Sprintf("\n\n\t// GraphQL API on the same router, over the same adapters\n\trouter.Handler(http.MethodPost, \"/%s\", graphqlapi.NewHandler(verifier, %s%s))", graphQLRoute, variables["auth_arguments"], serviceParams.String())
+		variables["endpoint_logging"] += fmt.Sprintf("\n\tlog.Info(\"  POST /%s - GraphQL queries and mutations\")", graphQLRoute)
 	}
 
 	content, err := processTemplate("main-go", variables)
@@ -87,7 +129,16 @@ func generateMainGo(moduleName string, tables []Table) string {
 // generateReadme creates README.md content using templates
 func generateReadme(moduleName string) string {
 	variables := map[string]string{
-		"module_name": moduleName,
+		"module_name":     moduleName,
+		"tenant_claim":    tenantClaim(),
+		"graphql_section": "",
+	}
+	if usesGraphQL() {
+		variables["graphql_section"] = fmt.Sprintf("\n## GraphQL API\n"+
+			"`POST /%s` answers GraphQL over the same adapters. `internal/interactor/graphql/schema.graphql` declares a type per table "+
+			"with a get and a list query, `create`, `update` and `delete` mutations, and a relationship field per foreign key whose "+
+			"lookups are batched per request. Queries authenticate like the REST routes, may nest at most %d levels and report "+
+			"errors with the code of their domain error in `extensions.code`.\n", graphQLRoute, graphQLMaxDepth())
 	}
 
 	content, err := processTemplate("readme", variables)
@@ -100,7 +151,9 @@ func generateReadme(moduleName string) string {
 // generateConfig creates configuration structure using templates
 func generateConfig(moduleName string) string {
 	variables := map[string]string{
-		"module_name": moduleName,
+		"module_name":   moduleName,
+		"query_timeout": queryTimeout(),
+		"tenant_claim":  tenantClaim(),
 	}
 
 	content, err := processTemplate("config", variables)
@@ -135,3 +188,105 @@ func generateMetaField() string {
 	}
 	return content
 }
+
+// generateQueryModel creates the filter and sort types shared by all layers
+func generateQueryModel(moduleName string) string {
+	variables := map[string]string{
+		"module_name":   moduleName,
+		"max_page_size": fmt.Sprint(maxPageSize()),
+	}
+
+	content, err := processTemplate("query-model", variables)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to generate query model: %v", err))
+	}
+	return content
+}
+
+// generatePostgresQuery creates the filter and sort clause builders for repositories
+func generatePostgresQuery(moduleName string) string {
+	variables := map[string]string{
+		"module_name": moduleName,
+	}
+
+	content, err := processTemplate("postgres-query", variables)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to generate postgres query helpers: %v", err))
+	}
+	return content
+}
+
+// generatePostgresTransaction creates the transactions that repository calls can take part in
+func generatePostgresTransaction() string {
+	content, err := processTemplate("postgres-transaction", map[string]string{})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to generate postgres transactions: %v", err))
+	}
+	return content
+}
+
+// generateDomainErrors creates the typed domain errors shared by every layer
+func generateDomainErrors() string {
+	content, err := processTemplate("domain-errors", map[string]string{})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to generate domain errors: %v", err))
+	}
+	return content
+}
+
+// generatePostgresErrors creates the translation of database errors into domain errors
+func generatePostgresErrors(moduleName string) string {
+	variables := map[string]string{
+		"module_name": moduleName,
+	}
+
+	content, err := processTemplate("postgres-errors", variables)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to generate postgres error translation: %v", err))
+	}
+	return content
+}
+
+// generateAuthPrincipal creates the principal of authenticated requests and its context helpers
+func generateAuthPrincipal() string {
+	content, err := processTemplate("auth-principal", map[string]string{})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to generate auth principal: %v", err))
+	}
+	return content
+}
+
+// generateAuthJWT creates the JWT verifier used by the Authenticate middleware
+func generateAuthJWT(moduleName string) string {
+	variables := map[string]string{
+		"module_name": moduleName,
+	}
+
+	content, err := processTemplate("auth-jwt", variables)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to generate JWT verifier: %v", err))
+	}
+	return content
+}
+
+// generateAuthKeys creates the loading of verification keys from secrets, PEM files and JWKS files
+func generateAuthKeys() string {
+	content, err := processTemplate("auth-keys", map[string]string{})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to generate auth keys: %v", err))
+	}
+	return content
+}
+
+// generateAuthJWTTest creates the JWT verifier tests, which mint their own tokens
+func generateAuthJWTTest(moduleName string) string {
+	variables := map[string]string{
+		"module_name": moduleName,
+	}
+
+	content, err := processTemplate("auth-jwt-test", variables)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to generate JWT verifier tests: %v", err))
+	}
+	return content
+}
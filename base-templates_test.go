@@ -0,0 +1,22 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateGoModRequiresGoLibraryForWrapperOnly(t *testing.T) {
+	for _, tt := range []struct {
+		responses string
+		want      bool
+	}{
+		{"", true},
+		{responsesWrapper, true},
+		{responsesProblem, false},
+	} {
+		withConfig(t, Config{Responses: tt.responses})
+		if got := strings.Contains(generateGoMod("shop"), "github.com/RizkiAnurka/go-library"); got != tt.want {
+			t.Errorf("responses %q: go.mod requires go-library = %v, want %v", tt.responses, got, tt.want)
+		}
+	}
+}
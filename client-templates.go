@@ -0,0 +1,286 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// generateClientCore renders the client, its options and its envelope decoding
+func generateClientCore(moduleName string, tables []Table) (client, envelope string) {
+	var clientOptions, requestOptions string
+	if usesAPIKeys() {
+		clientOptions += `
+// WithAPIKey authenticates every request with an API key instead of a bearer token
+func WithAPIKey(key string) Option {
+	return WithHeader("X-API-Key", key)
+}
+`
+	}
+	if usesTenancy() && tenantSource() == tenantSourceHeader {
+		clientOptions += fmt.Sprintf(`
+// WithTenant scopes every request to a tenant
+func WithTenant(tenant string) Option {
+	return WithHeader(%q, tenant)
+}
+`, tenantHeader())
+	}
+	if slices.ContainsFunc(tables, usesVersioning) {
+		requestOptions = `
+// IfMatch names the version of a record a change expects, as versioned tables require
+func IfMatch(version int64) RequestOption {
+	return Header("If-Match", "\""+strconv.FormatInt(version, 10)+"\"")
+}
+`
+	}
+
+	client = mustProcessTemplate("client", map[string]string{
+		"module_name":     moduleName,
+		"client_options":  clientOptions,
+		"request_options": requestOptions,
+	})
+
+	envelopeTemplate := "client-envelope-wrapper"
+	if usesProblemDetails() {
+		envelopeTemplate = "client-envelope-problem"
+	}
+	return client, mustProcessTemplate(envelopeTemplate, map[string]string{})
+}
+
+// generateClientEntity renders the typed methods of a table's endpoints
+func generateClientEntity(moduleName string, table Table) string {
+	names := namesFor(table)
+	pageType, listFunc := "Page", "listPage"
+	if usesCursorPagination(table) {
+		pageType, listFunc = "CursorPage", "listCursor"
+	}
+
+	return mustProcessTemplate("client-entity", map[string]string{
+		"module_name":   moduleName,
+		"struct_name":   names.Struct,
+		"plural_name":   names.Plural,
+		"entity_name":   names.Entity,
+		"entity_plural": names.EntityPlural,
+		"page_type":     pageType,
+		"list_func":     listFunc,
+	})
+}
+
+// generateClientTest renders the tests of the client against the REST handlers over fake services
+func generateClientTest(moduleName string, tables []Table) string {
+	var entityTests strings.Builder
+	apiArgs := make([]string, 0, len(tables)+1)
+	if usesAPIKeys() {
+		apiArgs = append(apiArgs, "nil")
+	}
+
+	var roles []string
+	for _, table := range tables {
+		names := namesFor(table)
+		entityTests.WriteString(generateClientTestEntity(table))
+		apiArgs = append(apiArgs, "fakes."+names.Struct)
+
+		access := accessFor(table)
+		for _, group := range [][]string{access.Read, access.Write, access.Delete, adminRolesFor(table), access.OwnerBypass} {
+			roles = append(roles, group...)
+		}
+	}
+	slices.Sort(roles)
+
+	vars := map[string]string{
+		"module_name":         moduleName,
+		"entity_tests":        entityTests.String(),
+		"api_args":            strings.Join(apiArgs, ", "),
+		"roles":               quotedList(slices.Compact(roles)),
+		"tenant_claim_config": "",
+		"tenant_claim":        "",
+		"tenant_option":       "",
+		"first_struct":        namesFor(tables[0]).Struct,
+		"first_plural":        namesFor(tables[0]).Plural,
+	}
+	if usesTenancy() {
+		switch tenantSource() {
+		case tenantSourceHeader:
+			vars["tenant_option"] = fmt.Sprintf(", client.WithHeader(%q, \"acme\")", tenantHeader())
+		case tenantSourceSubdomain:
+			vars["tenant_option"] = `, client.WithRequestEditor(func(req *http.Request) error {
+		req.Host = "acme.api.example.com"
+		return nil
+	})`
+		default:
+			vars["tenant_claim_config"] = fmt.Sprintf(", TenantClaim: %q", tenantClaim())
+			vars["tenant_claim"] = fmt.Sprintf("\n\t\t%q: \"acme\",", tenantClaim())
+		}
+	}
+	return mustProcessTemplate("client-test", vars)
+}
+
+// clientSampleField returns a request DTO member setting a column of the given request type to a valid value
+func clientSampleField(col Column, requestType string) string {
+	value := sampleLiteral(col)
+	if strings.HasPrefix(requestType, "*") {
+		value = fmt.Sprintf("ptr(%s(%s))", col.GoType, value)
+	}
+	return fmt.Sprintf("%s: %s", col.FieldName, value)
+}
+
+// generateClientTestEntity renders the test of the client methods of a table
+func generateClientTestEntity(table Table) string {
+	names := namesFor(table)
+
+	var required []Column
+	for _, col := range table.Columns {
+		if col.inCreateRequest() && col.requiresValue() {
+			required = append(required, col)
+		}
+	}
+
+	fields := make([]string, len(required))
+	for i, col := range required {
+		fields[i] = clientSampleField(col, col.requestType())
+	}
+
+	// Replacements take every NOT NULL member, defaulted ones included
+	var updateFields []string
+	for _, col := range table.Columns {
+		if col.inUpdateRequest() && col.requiredOnUpdate() {
+			updateFields = append(updateFields, clientSampleField(col, col.updateType()))
+		}
+	}
+
+	var patchFields, validationCheck string
+	if len(required) > 0 {
+		col := required[0]
+		value := sampleLiteral(col)
+		if col.GoType == "int64" || col.GoType == "float64" {
+			value = fmt.Sprintf("%s(%s)", col.GoType, value)
+		}
+		patchFields = fmt.Sprintf("%q: %s", strings.ToLower(col.Name), value)
+
+		validationCheck = fmt.Sprintf(`	_, err := c.Create%[1]s(ctx, client.Create%[1]sRequest{})
+	var invalid *client.Error
+	if !errors.Is(err, client.ErrValidation) || !errors.As(err, &invalid) || len(invalid.Fields) == 0 {
+		t.Fatalf("Create%[1]s() without required fields error = %%v, want ErrValidation listing the fields", err)
+	}
+
+`, names.Struct)
+	}
+
+	vars := map[string]string{
+		"struct_name":      names.Struct,
+		"plural_name":      names.Plural,
+		"entity_name":      names.Entity,
+		"create_fields":    strings.Join(fields, ", "),
+		"update_fields":    strings.Join(updateFields, ", "),
+		"patch_fields":     patchFields,
+		"validation_check": validationCheck,
+		"list_check":       " || page.Total != 1",
+		"change_options":   "",
+	}
+	if usesCursorPagination(table) {
+		vars["list_check"] = ""
+	}
+	if usesVersioning(table) {
+		vars["change_options"] = ", client.IfMatch(1)"
+	}
+	return mustProcessTemplate("client-test-entity", vars)
+}
+
+// sampleLiteral returns a Go literal of the column's type that passes its validation rules
+func sampleLiteral(col Column) string {
+	rules := col.validationRules()
+	switch col.GoType {
+	case "bool":
+		return "true"
+	case "time.Time":
+		return "time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)"
+	case "[]string":
+		return `[]string{"a"}`
+	case "int64", "float64":
+		return sampleNumber(col.GoType == "int64", rules)
+	}
+
+	value := "sample"
+	for _, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "oneof":
+			return strconv.Quote(strings.Fields(param)[0])
+		case "email":
+			value = "user@example.com"
+		case "url":
+			value = "https://example.com"
+		case "uuid":
+			value = "123e4567-e89b-12d3-a456-426614174000"
+		}
+	}
+	for _, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+		n, _ := strconv.Atoi(param)
+		switch {
+		case name == "min" && len(value) < n:
+			value += strings.Repeat("x", n-len(value))
+		case name == "max" && len(value) > n && n > 0:
+			value = value[:n]
+		}
+	}
+	return strconv.Quote(value)
+}
+
+// sampleNumber returns a number within the bounds of the comparison rules, preferring 1
+func sampleNumber(integer bool, rules []string) string {
+	value := 1.0
+	var excluded []float64
+	for _, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+		bound, err := strconv.ParseFloat(strings.Fields(param + " 0")[0], 64)
+		if err != nil {
+			continue
+		}
+		switch name {
+		case "eq", "oneof":
+			return strconv.FormatFloat(bound, 'f', -1, 64)
+		case "gt":
+			value = max(value, bound+1)
+		case "gte":
+			value = max(value, bound)
+		case "lt":
+			value = min(value, bound-1)
+		case "lte":
+			value = min(value, bound)
+		case "ne":
+			excluded = append(excluded, bound)
+		}
+	}
+	for slices.Contains(excluded, value) {
+		value++
+	}
+	if integer {
+		value = float64(int64(value))
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// generateClient creates the typed Go client of the REST API in pkg/client
+func generateClient(moduleName string, tables []Table) error {
+	clientDir := filepath.Join(moduleName, "pkg", "client")
+	client, envelope := generateClientCore(moduleName, tables)
+	files := map[string]string{
+		filepath.Join(clientDir, "client.go"):      client,
+		filepath.Join(clientDir, "envelope.go"):    envelope,
+		filepath.Join(clientDir, "client_test.go"): generateClientTest(moduleName, tables),
+	}
+	for _, table := range tables {
+		files[filepath.Join(clientDir, namesFor(table).EntityPlural+".go")] = generateClientEntity(moduleName, table)
+	}
+
+	for filePath, content := range files {
+		if err := writeFile(filePath, content); err != nil {
+			return err
+		}
+		fmt.Printf("Created client: %s\n", filePath)
+	}
+	return nil
+}
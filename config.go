@@ -0,0 +1,632 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Config holds optional generator settings loaded from a boGO config file
+type Config struct {
+	Inflections  InflectionConfig       `json:"inflections"`
+	Initialisms  []string               `json:"initialisms"`
+	Auth         AuthConfig             `json:"auth"`
+	Audit        AuditConfig            `json:"audit"`
+	Batch        BatchConfig            `json:"batch"`
+	GraphQL      *GraphQLConfig         `json:"graphql"`
+	MaxPageSize  int                    `json:"max_page_size"`
+	QueryTimeout string                 `json:"query_timeout"`
+	Responses    string                 `json:"responses"`
+	Tables       map[string]TableConfig `json:"tables"`
+	Tenancy      *TenancyConfig         `json:"tenancy"`
+}
+
+// InflectionConfig extends the built-in English inflection rules
+type InflectionConfig struct {
+	// Irregular maps singular words to their plural form, e.g. {"cactus": "cactuses"}
+	Irregular map[string]string `json:"irregular"`
+	// Uncountable lists words that have no distinct plural form
+	Uncountable []string `json:"uncountable"`
+}
+
+// AuthConfig enables authentication modes in addition to bearer tokens
+type AuthConfig struct {
+	// APIKeys generates hashed API key storage, an admin CLI and X-API-Key authentication
+	APIKeys bool `json:"api_keys"`
+}
+
+// AuditConfig records who changed which records
+type AuditConfig struct {
+	// Columns adds created_by, updated_by and deleted_by to every table, filled from the request principal
+	Columns bool `json:"columns"`
+	// Log writes the changed fields of every create, update and delete to an audit_log table
+	Log bool `json:"log"`
+}
+
+// BatchConfig limits the batch endpoints of every table
+type BatchConfig struct {
+	// MaxItems caps the items of one batch request, defaults to 1000
+	MaxItems int `json:"max_items"`
+	// MaxBytes caps the body of one batch request, defaults to 10 MiB
+	MaxBytes int64 `json:"max_bytes"`
+	// ChunkSize is the number of items written in one statement, defaults to 100
+	ChunkSize int `json:"chunk_size"`
+}
+
+// GraphQLConfig adds a GraphQL endpoint next to the REST API. It is enabled by its presence.
+type GraphQLConfig struct {
+	// MaxDepth caps the nesting of the fields of a query, defaults to 10
+	MaxDepth int `json:"max_depth"`
+}
+
+// TenancyConfig scopes every table to the tenant of the request. Tenancy is enabled by its presence.
+type TenancyConfig struct {
+	// Column holds the tenant of each row, defaults to tenant_id
+	Column string `json:"column"`
+	// Source resolves the tenant of a request: "claim" (default), "header" or "subdomain"
+	Source string `json:"source"`
+	// Claim is the default JWT claim naming the tenant, defaults to tenant_id
+	Claim string `json:"claim"`
+	// Header names the tenant for the header source, defaults to X-Tenant-ID
+	Header string `json:"header"`
+	// SharedTables lists tables without a tenant column that every tenant may access
+	SharedTables []string `json:"shared_tables"`
+}
+
+// TableConfig holds per-table overrides keyed by SQL table name
+type TableConfig struct {
+	// Singular overrides the singular entity name derived from the table name
+	Singular string `json:"singular"`
+	// Plural overrides the plural entity name, which defaults to the table name
+	Plural string `json:"plural"`
+	// Pagination selects the list endpoint mode: "offset" (default) or "cursor"
+	Pagination string `json:"pagination"`
+	// CursorColumn is the sortable column used for cursor pagination, defaults to id
+	CursorColumn string `json:"cursor_column"`
+	// Access is the role-based access policy of the table's routes and rows
+	Access AccessConfig `json:"access"`
+	// Delete selects how rows are deleted: "soft" (default), which keeps them restorable, or "hard"
+	Delete string `json:"delete"`
+	// Versioned adds a version column that every change increments, so concurrent changes cannot overwrite each other
+	Versioned bool `json:"versioned"`
+	// UpsertKey lists the columns of a unique key that batch upserts match rows on, defaults to id
+	UpsertKey []string `json:"upsert_key"`
+}
+
+// AccessConfig lists the roles allowed per operation; a principal needs any one of them.
+// Operations without roles are open to every authenticated principal.
+type AccessConfig struct {
+	// Read guards the list and get routes
+	Read []string `json:"read"`
+	// Write guards the create, update and patch routes
+	Write []string `json:"write"`
+	// Delete guards the delete route
+	Delete []string `json:"delete"`
+	// Admin guards restoring, purging and listing soft-deleted rows, defaults to the admin role
+	Admin []string `json:"admin"`
+	// OwnerColumn limits reads and changes to rows whose column holds the principal's subject
+	OwnerColumn string `json:"owner_column"`
+	// OwnerBypass lists the roles exempt from the ownership rule
+	OwnerBypass []string `json:"owner_bypass"`
+}
+
+// Pagination modes for list endpoints
+const (
+	paginationOffset = "offset"
+	paginationCursor = "cursor"
+)
+
+// Delete modes
+const (
+	deleteSoft = "soft"
+	deleteHard = "hard"
+)
+
+// defaultAdminRole guards the soft delete administration routes when no admin roles are configured
+const defaultAdminRole = "admin"
+
+// Tenant sources
+const (
+	tenantSourceClaim     = "claim"
+	tenantSourceHeader    = "header"
+	tenantSourceSubdomain = "subdomain"
+)
+
+// REST response formats
+const (
+	responsesWrapper = "wrapper"
+	responsesProblem = "problem"
+)
+
+// defaultMaxPageSize caps the limit of list endpoints when max_page_size is not configured
+const defaultMaxPageSize = 100
+
+// Batch endpoint limits used when the batch config leaves them unset
+const (
+	defaultBatchMaxItems  = 1000
+	defaultBatchMaxBytes  = 10 << 20
+	defaultBatchChunkSize = 100
+)
+
+// defaultGraphQLMaxDepth caps the nesting of GraphQL queries when max_depth is not configured
+const defaultGraphQLMaxDepth = 10
+
+// defaultQueryTimeout bounds every repository query when query_timeout is not configured
+const defaultQueryTimeout = "5s"
+
+// generatorConfig is the configuration used by every generator for the current run
+var generatorConfig Config
+
+// loadConfig reads a JSON boGO config file. An empty filename yields the default configuration.
+func loadConfig(filename string) (Config, error) {
+	var cfg Config
+	if filename == "" {
+		return cfg, nil
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read config file: %v", err)
+	}
+	if err := json.Unmarshal(content, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse config file %s: %v", filename, err)
+	}
+	return cfg, nil
+}
+
+// validateConfig checks the per-table settings against the parsed schema
+func validateConfig(cfg Config, tables []Table) error {
+	if cfg.MaxPageSize < 0 {
+		return fmt.Errorf("max_page_size must not be negative")
+	}
+	if cfg.QueryTimeout != "" {
+		if timeout, err := time.ParseDuration(cfg.QueryTimeout); err != nil || timeout < 0 {
+			return fmt.Errorf("query_timeout %q is not a valid non-negative duration", cfg.QueryTimeout)
+		}
+	}
+	if cfg.Batch.MaxItems < 0 || cfg.Batch.MaxBytes < 0 || cfg.Batch.ChunkSize < 0 {
+		return fmt.Errorf("batch limits must not be negative")
+	}
+	if cfg.GraphQL != nil && cfg.GraphQL.MaxDepth < 0 {
+		return fmt.Errorf("graphql.max_depth must not be negative")
+	}
+	switch cfg.Responses {
+	case "", responsesWrapper, responsesProblem:
+	default:
+		return fmt.Errorf("unknown responses format %q", cfg.Responses)
+	}
+
+	known := map[string]Table{}
+	for _, table := range tables {
+		known[table.Name] = table
+		if cfg.Auth.APIKeys && strings.EqualFold(table.Name, apiKeyTable) {
+			return fmt.Errorf("table %s is reserved for API keys", table.Name)
+		}
+		if cfg.Audit.Log && strings.EqualFold(table.Name, auditLogTable) {
+			return fmt.Errorf("table %s is reserved for the audit log", table.Name)
+		}
+		if err := validateAuditColumns(table); err != nil {
+			return fmt.Errorf("table %s: %v", table.Name, err)
+		}
+	}
+	for name, tableCfg := range cfg.Tables {
+		table, ok := known[name]
+		if !ok {
+			return fmt.Errorf("config references unknown table %q", name)
+		}
+		switch tableCfg.Pagination {
+		case "", paginationOffset, paginationCursor:
+		default:
+			return fmt.Errorf("table %s: unknown pagination mode %q", name, tableCfg.Pagination)
+		}
+		if tableCfg.CursorColumn != "" {
+			if _, ok := cursorColumnFor(table); !ok {
+				return fmt.Errorf("table %s: cursor_column %q is not a column", name, tableCfg.CursorColumn)
+			}
+		}
+		if err := validateAccess(table, tableCfg.Access); err != nil {
+			return fmt.Errorf("table %s: %v", name, err)
+		}
+		switch tableCfg.Delete {
+		case "", deleteSoft, deleteHard:
+		default:
+			return fmt.Errorf("table %s: unknown delete mode %q", name, tableCfg.Delete)
+		}
+		if err := validateUpsertKey(table, tableCfg.UpsertKey); err != nil {
+			return fmt.Errorf("table %s: %v", name, err)
+		}
+		for _, col := range table.Columns {
+			switch {
+			case !usesSoftDelete(table) && strings.ToLower(col.Name) == "deleted_at":
+				return fmt.Errorf("table %s: column deleted_at requires soft deletes", name)
+			case usesVersioning(table) && strings.ToLower(col.Name) == "version":
+				return fmt.Errorf("table %s: column version is provided by versioning, remove it from the schema", name)
+			}
+		}
+	}
+	return validateTenancy(tables)
+}
+
+// uniqueConstraint matches the column list of a table-level UNIQUE constraint
+var uniqueConstraint = regexp.MustCompile(`(?i)\bUNIQUE\s*\(([^)]*)\)`)
+
+// validateUpsertKey checks that an upsert key names required columns that clients set on create
+// and that the schema declares unique together, as ON CONFLICT needs a matching unique index
+func validateUpsertKey(table Table, key []string) error {
+	if len(key) == 0 || (len(key) == 1 && strings.EqualFold(key[0], "id")) {
+		return nil
+	}
+
+	names := make([]string, len(key))
+	for i, name := range key {
+		column, ok := tableColumn(table, name)
+		if !ok {
+			return fmt.Errorf("upsert_key column %q is not a column", name)
+		}
+		if column.IsNullable || column.IsGenerated {
+			return fmt.Errorf("upsert_key column %q must be a NOT NULL column that is not generated", name)
+		}
+		if tenant, ok := tenantColumnFor(table); !column.inCreateRequest() && !(ok && tenant.Name == column.Name) {
+			return fmt.Errorf("upsert_key column %q is not set by clients on create", name)
+		}
+		names[i] = strings.ToLower(column.Name)
+	}
+	slices.Sort(names)
+	if len(slices.Compact(slices.Clone(names))) != len(names) {
+		return fmt.Errorf("upsert_key lists a column twice")
+	}
+
+	if len(names) == 1 {
+		if column, _ := tableColumn(table, names[0]); column.IsUnique || column.IsPrimaryKey {
+			return nil
+		}
+	}
+	for _, constraint := range table.Constraints {
+		for _, match := range uniqueConstraint.FindAllStringSubmatch(constraint, -1) {
+			var columns []string
+			for _, name := range strings.Split(match[1], ",") {
+				columns = append(columns, strings.ToLower(strings.Trim(strings.TrimSpace(name), `"`)))
+			}
+			slices.Sort(columns)
+			if slices.Equal(columns, names) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("upsert_key (%s) is not declared UNIQUE", strings.Join(key, ", "))
+}
+
+// validateAuditColumns checks that audit columns declared in the schema can hold a principal's subject
+func validateAuditColumns(table Table) error {
+	if !usesAuditColumns() {
+		return nil
+	}
+	for _, col := range table.Columns {
+		if auditColumns[strings.ToLower(col.Name)] && col.GoType != "string" {
+			return fmt.Errorf("audit column %q must be a text column", col.Name)
+		}
+	}
+	return nil
+}
+
+// headerName matches the HTTP header names that can be written into generated string literals
+var headerName = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+
+// validateTenancy checks that every table not shared between tenants has an integer or text tenant column
+func validateTenancy(tables []Table) error {
+	if !usesTenancy() {
+		return nil
+	}
+	switch generatorConfig.Tenancy.Source {
+	case "", tenantSourceClaim, tenantSourceHeader, tenantSourceSubdomain:
+	default:
+		return fmt.Errorf("unknown tenancy source %q", generatorConfig.Tenancy.Source)
+	}
+	if !headerName.MatchString(tenantHeader()) {
+		return fmt.Errorf("invalid tenancy header %q", tenantHeader())
+	}
+	if !roleName.MatchString(tenantClaim()) {
+		return fmt.Errorf("invalid tenancy claim %q", tenantClaim())
+	}
+
+	known := map[string]bool{}
+	for _, table := range tables {
+		known[table.Name] = true
+	}
+	for _, name := range generatorConfig.Tenancy.SharedTables {
+		if !known[name] {
+			return fmt.Errorf("tenancy shares unknown table %q", name)
+		}
+	}
+
+	for _, table := range tables {
+		if slices.Contains(generatorConfig.Tenancy.SharedTables, table.Name) {
+			continue
+		}
+		column, ok := tenantColumnFor(table)
+		if !ok {
+			return fmt.Errorf("table %s: missing tenant column %q (list it in tenancy.shared_tables to share it)", table.Name, tenantColumn())
+		}
+		if column.GoType != "int64" && column.GoType != "string" {
+			return fmt.Errorf("table %s: tenant column %q must be an integer or text column", table.Name, column.Name)
+		}
+		if strings.EqualFold(accessFor(table).OwnerColumn, column.Name) {
+			return fmt.Errorf("table %s: tenant column %q cannot also be the owner column", table.Name, column.Name)
+		}
+	}
+	return nil
+}
+
+// roleName matches the role names that can be written into generated string literals
+var roleName = regexp.MustCompile(`^[A-Za-z0-9_.:-]+$`)
+
+// validateAccess checks the role names and owner column of a table's access policy
+func validateAccess(table Table, access AccessConfig) error {
+	for _, roles := range [][]string{access.Read, access.Write, access.Delete, access.Admin, access.OwnerBypass} {
+		for _, role := range roles {
+			if !roleName.MatchString(role) {
+				return fmt.Errorf("invalid role name %q", role)
+			}
+		}
+	}
+	if access.OwnerColumn == "" {
+		if len(access.OwnerBypass) > 0 {
+			return fmt.Errorf("owner_bypass requires owner_column")
+		}
+		return nil
+	}
+	column, ok := ownerColumnFor(table)
+	if !ok {
+		return fmt.Errorf("owner_column %q is not a column", access.OwnerColumn)
+	}
+	if column.GoType != "int64" && column.GoType != "string" {
+		return fmt.Errorf("owner_column %q must be an integer or text column", access.OwnerColumn)
+	}
+	return nil
+}
+
+// maxPageSize returns the configured page size cap for list endpoints
+func maxPageSize() int {
+	if generatorConfig.MaxPageSize > 0 {
+		return generatorConfig.MaxPageSize
+	}
+	return defaultMaxPageSize
+}
+
+// queryTimeout returns the default DB_QUERY_TIMEOUT of the generated service
+func queryTimeout() string {
+	if generatorConfig.QueryTimeout != "" {
+		return generatorConfig.QueryTimeout
+	}
+	return defaultQueryTimeout
+}
+
+// usesAPIKeys reports whether the generated service accepts X-API-Key credentials
+func usesAPIKeys() bool {
+	return generatorConfig.Auth.APIKeys
+}
+
+// usesGraphQL reports whether the generated service serves a GraphQL endpoint
+func usesGraphQL() bool {
+	return generatorConfig.GraphQL != nil
+}
+
+// graphQLMaxDepth returns the deepest nesting of fields a GraphQL query may have
+func graphQLMaxDepth() int {
+	if generatorConfig.GraphQL != nil && generatorConfig.GraphQL.MaxDepth > 0 {
+		return generatorConfig.GraphQL.MaxDepth
+	}
+	return defaultGraphQLMaxDepth
+}
+
+// usesAuditColumns reports whether tables carry created_by, updated_by and deleted_by columns
+func usesAuditColumns() bool {
+	return generatorConfig.Audit.Columns
+}
+
+// usesAuditLog reports whether changes are written to the audit_log table
+func usesAuditLog() bool {
+	return generatorConfig.Audit.Log
+}
+
+// usesProblemDetails reports whether REST handlers answer with RFC 7807 problem details
+// instead of the go-library response wrapper
+func usesProblemDetails() bool {
+	return generatorConfig.Responses == responsesProblem
+}
+
+// usesCursorPagination reports whether the table's list endpoint uses keyset pagination
+func usesCursorPagination(table Table) bool {
+	return generatorConfig.Tables[table.Name].Pagination == paginationCursor
+}
+
+// cursorColumn describes the column used to order keyset pagination
+type cursorColumn struct {
+	Name      string // SQL column name
+	FieldName string // Go field on the domain model
+	GoType    string // Go type of the field
+}
+
+// cursorColumnFor resolves the configured cursor column of a table, defaulting to id
+func cursorColumnFor(table Table) (cursorColumn, bool) {
+	name := strings.ToLower(generatorConfig.Tables[table.Name].CursorColumn)
+	switch name {
+	case "", "id":
+		return cursorColumn{Name: "id", FieldName: "ID", GoType: "int64"}, true
+	case "created_at":
+		return cursorColumn{Name: name, FieldName: "CreatedAt", GoType: "time.Time"}, true
+	case "updated_at":
+		return cursorColumn{Name: name, FieldName: "UpdatedAt", GoType: "time.Time"}, true
+	}
+	for _, col := range table.Columns {
+		if strings.ToLower(col.Name) == name && !metaColumns[name] {
+			return cursorColumn{Name: col.Name, FieldName: col.FieldName, GoType: col.GoType}, true
+		}
+	}
+	return cursorColumn{}, false
+}
+
+// accessFor returns the access policy configured for a table
+func accessFor(table Table) AccessConfig {
+	return generatorConfig.Tables[table.Name].Access
+}
+
+// adminRolesFor returns the roles allowed to restore, purge and list soft-deleted rows of a table
+func adminRolesFor(table Table) []string {
+	if roles := accessFor(table).Admin; len(roles) > 0 {
+		return roles
+	}
+	return []string{defaultAdminRole}
+}
+
+// usesSoftDelete reports whether deleting a row of the table only marks it as deleted
+func usesSoftDelete(table Table) bool {
+	return generatorConfig.Tables[table.Name].Delete != deleteHard
+}
+
+// batchMaxItems returns the most items one batch request may hold
+func batchMaxItems() int {
+	if generatorConfig.Batch.MaxItems > 0 {
+		return generatorConfig.Batch.MaxItems
+	}
+	return defaultBatchMaxItems
+}
+
+// batchMaxBytes returns the largest body one batch request may have
+func batchMaxBytes() int64 {
+	if generatorConfig.Batch.MaxBytes > 0 {
+		return generatorConfig.Batch.MaxBytes
+	}
+	return defaultBatchMaxBytes
+}
+
+// batchChunkSize returns the number of batch items written in one statement
+func batchChunkSize() int {
+	if generatorConfig.Batch.ChunkSize > 0 {
+		return generatorConfig.Batch.ChunkSize
+	}
+	return defaultBatchChunkSize
+}
+
+// upsertKeyFor returns the columns batch upserts of a table match rows on, in config order.
+// The default key is the primary key id.
+func upsertKeyFor(table Table) []Column {
+	var key []Column
+	for _, name := range generatorConfig.Tables[table.Name].UpsertKey {
+		if column, ok := tableColumn(table, name); ok && !strings.EqualFold(name, "id") {
+			key = append(key, column)
+		}
+	}
+	if len(key) == 0 {
+		return []Column{{Name: "id", FieldName: "ID", GoType: "int64", IsPrimaryKey: true}}
+	}
+	return key
+}
+
+// tableColumn finds a column of a table by its case-insensitive name
+func tableColumn(table Table, name string) (Column, bool) {
+	for _, col := range table.Columns {
+		if strings.EqualFold(col.Name, name) {
+			return col, true
+		}
+	}
+	return Column{}, false
+}
+
+// usesVersioning reports whether changes of the table's rows are guarded by a version column
+func usesVersioning(table Table) bool {
+	return generatorConfig.Tables[table.Name].Versioned
+}
+
+// ownerColumnFor resolves the owner column of a table, if ownership rules are configured
+func ownerColumnFor(table Table) (Column, bool) {
+	name := strings.ToLower(accessFor(table).OwnerColumn)
+	if name == "" {
+		return Column{}, false
+	}
+	for _, col := range table.Columns {
+		if strings.ToLower(col.Name) == name && !col.isMeta() {
+			return col, true
+		}
+	}
+	return Column{}, false
+}
+
+// usesTenancy reports whether tables are scoped to the tenant of the request
+func usesTenancy() bool {
+	return generatorConfig.Tenancy != nil
+}
+
+// tenantSource returns how the generated service resolves the tenant of a request
+func tenantSource() string {
+	if generatorConfig.Tenancy.Source != "" {
+		return generatorConfig.Tenancy.Source
+	}
+	return tenantSourceClaim
+}
+
+// tenantColumn returns the name of the tenant column
+func tenantColumn() string {
+	if generatorConfig.Tenancy.Column != "" {
+		return strings.ToLower(generatorConfig.Tenancy.Column)
+	}
+	return "tenant_id"
+}
+
+// tenantClaim returns the default JWT claim naming the tenant
+func tenantClaim() string {
+	if generatorConfig.Tenancy != nil && generatorConfig.Tenancy.Claim != "" {
+		return generatorConfig.Tenancy.Claim
+	}
+	return "tenant_id"
+}
+
+// tenantHeader returns the request header naming the tenant for the header source
+func tenantHeader() string {
+	if generatorConfig.Tenancy.Header != "" {
+		return generatorConfig.Tenancy.Header
+	}
+	return "X-Tenant-ID"
+}
+
+// tenantColumnFor resolves the tenant column of a table that is scoped to tenants
+func tenantColumnFor(table Table) (Column, bool) {
+	if !usesTenancy() || slices.Contains(generatorConfig.Tenancy.SharedTables, table.Name) {
+		return Column{}, false
+	}
+	for _, col := range table.Columns {
+		if strings.ToLower(col.Name) == tenantColumn() && !col.isMeta() {
+			return col, true
+		}
+	}
+	return Column{}, false
+}
+
+// markTenantColumns makes tenant columns read-only and keeps them out of create requests,
+// so clients can never choose the tenant of a row
+func markTenantColumns(tables []Table) {
+	for i, table := range tables {
+		column, ok := tenantColumnFor(table)
+		if !ok {
+			continue
+		}
+		for j, col := range table.Columns {
+			if col.Name == column.Name {
+				tables[i].Columns[j].IsReadOnly = true
+				tables[i].Columns[j].IsTenant = true
+				generateGoFieldInfo(&tables[i].Columns[j])
+			}
+		}
+	}
+}
+
+// applyConfig makes cfg the active configuration for all generators
+func applyConfig(cfg Config) {
+	generatorConfig = cfg
+	inflect = newInflector(cfg.Inflections)
+	initialisms = newInitialismSet(cfg.Initialisms)
+}
@@ -8,7 +8,14 @@ import (
 )
 
 // createHexagonalArchitecture creates the complete hexagonal architecture
-func createHexagonalArchitecture(moduleName, sqlSchemaFile string) error {
+func createHexagonalArchitecture(moduleName, sqlSchemaFile, configFile string) error {
+	// Load generator configuration (inflection overrides, per-table settings)
+	cfg, err := loadConfig(configFile)
+	if err != nil {
+		return err
+	}
+	applyConfig(cfg)
+
 	// Parse SQL schema to extract table information
 	tables, err := parseSQLSchema(sqlSchemaFile)
 	if err != nil {
@@ -20,6 +27,14 @@ func createHexagonalArchitecture(moduleName, sqlSchemaFile string) error {
 		fmt.Printf("  - Table: %s (%d columns)\n", table.Name, len(table.Columns))
 	}
 
+	if err := validateEntityNames(tables); err != nil {
+		return err
+	}
+	if err := validateConfig(cfg, tables); err != nil {
+		return err
+	}
+	markTenantColumns(tables)
+
 	// Validate that we have tables to generate code for
 	if len(tables) == 0 {
 		return fmt.Errorf("❌ No tables found in SQL schema '%s'.\n"+
@@ -84,12 +99,17 @@ func createDirectoryStructure(moduleName string) error {
 		filepath.Join(moduleName, "internal", "interactor"),
 		filepath.Join(moduleName, "internal", "interactor", "rest"),
 		filepath.Join(moduleName, "internal", "interactor", "grpc"),
+		filepath.Join(moduleName, "internal", "interactor", "grpc", "pb"),
+		filepath.Join(moduleName, "proto"),
 
 		// Repository layer
 		filepath.Join(moduleName, "internal", "repository"),
 		filepath.Join(moduleName, "internal", "repository", "implementor", "postgres"),
 		filepath.Join(moduleName, "internal", "repository", "implementor", "cache"),
 
+		// Fake services of the API tests
+		filepath.Join(moduleName, "internal", "testsupport"),
+
 		// Package dependencies
 		filepath.Join(moduleName, "pkg"),
 
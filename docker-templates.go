@@ -71,6 +71,7 @@ func generateCrossPlatformBuildScript(moduleName string) string {
 func generateMakefile(moduleName string) string {
 	variables := map[string]string{
 		"module_name": moduleName,
+		"proto_dir":   protoDir(moduleName),
 	}
 
 	result, err := processTemplate("makefile", variables)
@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
 // generateAllFiles creates all template files for the hexagonal architecture
 func generateAllFiles(moduleName string, tables []Table) error {
+	generatedFiles = map[string]bool{}
+
 	// Generate base files
 	if err := generateBaseFiles(moduleName, tables); err != nil {
 		return err
@@ -54,6 +57,45 @@ func generateAllFiles(moduleName string, tables []Table) error {
 		return err
 	}
 
+	// Generate the Go client of the REST API
+	if err := generateClient(moduleName, tables); err != nil {
+		return err
+	}
+
+	// Generate the fake services the API tests run against
+	if err := generateTestSupport(moduleName, tables); err != nil {
+		return err
+	}
+
+	// Generate the TypeScript client of the REST API
+	if err := generateTSClientPackage(moduleName, tables); err != nil {
+		return err
+	}
+
+	// Generate the gRPC API over the same interactor services
+	if err := generateGRPC(moduleName, tables); err != nil {
+		return err
+	}
+
+	// Generate the optional GraphQL API over the same interactor services
+	if usesGraphQL() {
+		if err := generateGraphQL(moduleName, tables); err != nil {
+			return err
+		}
+	}
+
+	// Generate API key authentication
+	if usesAPIKeys() {
+		if err := generateAPIKeyAuth(moduleName); err != nil {
+			return err
+		}
+	}
+
+	// Generate audit columns and the audit log
+	if err := generateAudit(moduleName); err != nil {
+		return err
+	}
+
 	// Generate Goose migration with schema support
 	schema := ""
 	if moduleName == "wearable-service" {
@@ -88,6 +130,29 @@ func generateBaseFiles(moduleName string, tables []Table) error {
 		// MetaField (common fields)
 		filepath.Join(moduleName, "internal", "domain", "model", "meta.go"): generateMetaField(),
 
+		// Filter and sort query types shared across layers
+		filepath.Join(moduleName, "internal", "domain", "model", "query.go"):      generateQueryModel(moduleName),
+		filepath.Join(moduleName, "internal", "domain", "model", "query_test.go"): mustProcessTemplate("query-model-test", map[string]string{"module_name": moduleName}),
+
+		// Filter and sort clause builders
+		filepath.Join(moduleName, "internal", "repository", "implementor", "postgres", "query.go"):      generatePostgresQuery(moduleName),
+		filepath.Join(moduleName, "internal", "repository", "implementor", "postgres", "query_test.go"): mustProcessTemplate("postgres-query-test", map[string]string{"module_name": moduleName}),
+
+		// Transactions spanning several repository calls
+		filepath.Join(moduleName, "internal", "repository", "implementor", "postgres", "transaction.go"):      generatePostgresTransaction(),
+		filepath.Join(moduleName, "internal", "repository", "implementor", "postgres", "transaction_test.go"): mustProcessTemplate("postgres-transaction-test", map[string]string{}),
+
+		// Typed domain errors and their translation from database errors
+		filepath.Join(moduleName, "internal", "domain", "errs", "errs.go"):                               generateDomainErrors(),
+		filepath.Join(moduleName, "internal", "repository", "implementor", "postgres", "errors.go"):      generatePostgresErrors(moduleName),
+		filepath.Join(moduleName, "internal", "repository", "implementor", "postgres", "errors_test.go"): mustProcessTemplate("postgres-errors-test", map[string]string{"module_name": moduleName}),
+
+		// Bearer token authentication
+		filepath.Join(moduleName, "internal", "auth", "principal.go"): generateAuthPrincipal(),
+		filepath.Join(moduleName, "internal", "auth", "jwt.go"):       generateAuthJWT(moduleName),
+		filepath.Join(moduleName, "internal", "auth", "keys.go"):      generateAuthKeys(),
+		filepath.Join(moduleName, "internal", "auth", "jwt_test.go"):  generateAuthJWTTest(moduleName),
+
 		// Docker files
 		filepath.Join(moduleName, "Dockerfile"):         generateDockerfile(moduleName),
 		filepath.Join(moduleName, "docker-compose.yml"): generateDockerCompose(moduleName),
@@ -156,14 +221,24 @@ func generateApplicationInterfaces(moduleName string, tables []Table) error {
 
 // generateApplicationServices creates concrete application service implementations
 func generateApplicationServices(moduleName string, tables []Table) error {
+	// Batch application shared by all services
+	batchFile := filepath.Join(moduleName, "internal", "application", "batch.go")
+	if err := writeFile(batchFile, generateApplicationBatch(moduleName)); err != nil {
+		return err
+	}
+	fmt.Printf("Created application batches: %s\n", batchFile)
+
+	batchTestFile := filepath.Join(moduleName, "internal", "application", "batch_test.go")
+	if err := writeFile(batchTestFile, generateApplicationBatchTest(moduleName)); err != nil {
+		return err
+	}
+	fmt.Printf("Created application batch tests: %s\n", batchTestFile)
+
 	for _, table := range tables {
-		structName := toCamelCase(table.Name)
-		if strings.HasSuffix(structName, "s") {
-			structName = structName[:len(structName)-1]
-		}
+		names := namesFor(table)
 
 		serviceContent := generateApplicationService(moduleName, table)
-		serviceFile := filepath.Join(moduleName, "internal", "application", strings.ToLower(structName)+"_service.go")
+		serviceFile := filepath.Join(moduleName, "internal", "application", names.Entity+"_service.go")
 
 		if err := writeFile(serviceFile, serviceContent); err != nil {
 			return err
@@ -175,19 +250,52 @@ func generateApplicationServices(moduleName string, tables []Table) error {
 
 // generateDTOs creates DTO structs for data transfer between layers
 func generateDTOs(moduleName string, tables []Table) error {
+	// Patch document parsing shared by all DTOs, in files no table's DTO can be named after
+	patchFile := filepath.Join(moduleName, "internal", "application", "dto", "patch_helpers.go")
+	if err := writeFile(patchFile, generateDTOPatch(moduleName)); err != nil {
+		return err
+	}
+	fmt.Printf("Created DTO patch parsing: %s\n", patchFile)
+
+	patchTestFile := filepath.Join(moduleName, "internal", "application", "dto", "patch_helpers_test.go")
+	if err := writeFile(patchTestFile, mustProcessTemplate("dto-patch-test", map[string]string{"module_name": moduleName})); err != nil {
+		return err
+	}
+
+	// Request validation shared by all DTOs, in files no table's DTO can be named after
+	validationFile := filepath.Join(moduleName, "internal", "application", "dto", "validation_helpers.go")
+	if err := writeFile(validationFile, generateDTOValidation(moduleName)); err != nil {
+		return err
+	}
+	fmt.Printf("Created DTO validation: %s\n", validationFile)
+
+	validationTestFile := filepath.Join(moduleName, "internal", "application", "dto", "validation_helpers_test.go")
+	if err := writeFile(validationTestFile, mustProcessTemplate("dto-validation-test", map[string]string{"module_name": moduleName})); err != nil {
+		return err
+	}
+
+	// Batch requests and results shared by all DTOs, in a file no table's DTO can be named after
+	batchFile := filepath.Join(moduleName, "internal", "application", "dto", "batch_helpers.go")
+	if err := writeFile(batchFile, mustProcessTemplate("dto-batch", map[string]string{})); err != nil {
+		return err
+	}
+	fmt.Printf("Created DTO batches: %s\n", batchFile)
+
 	for _, table := range tables {
-		structName := toCamelCase(table.Name)
-		if strings.HasSuffix(structName, "s") {
-			structName = structName[:len(structName)-1]
-		}
+		names := namesFor(table)
 
 		dtoContent := generateDTO(moduleName, table)
-		dtoFile := filepath.Join(moduleName, "internal", "application", "dto", strings.ToLower(structName)+".go")
+		dtoFile := filepath.Join(moduleName, "internal", "application", "dto", names.Entity+".go")
 
 		if err := writeFile(dtoFile, dtoContent); err != nil {
 			return err
 		}
 		fmt.Printf("Created DTO: %s\n", dtoFile)
+
+		testFile := filepath.Join(moduleName, "internal", "application", "dto", names.Entity+"_test.go")
+		if err := writeFile(testFile, generateDTOTest(moduleName, table)); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -225,13 +333,10 @@ func generateInteractorServices(moduleName string, tables []Table) error {
 // generateInteractorAdapters creates adapter implementations for interactor layer
 func generateInteractorAdapters(moduleName string, tables []Table) error {
 	for _, table := range tables {
-		structName := toCamelCase(table.Name)
-		if strings.HasSuffix(structName, "s") {
-			structName = structName[:len(structName)-1]
-		}
+		names := namesFor(table)
 
 		adapterContent := generateInteractorAdapter(moduleName, table)
-		adapterFile := filepath.Join(moduleName, "internal", "interactor", strings.ToLower(structName)+"_adapter.go")
+		adapterFile := filepath.Join(moduleName, "internal", "interactor", names.Entity+"_adapter.go")
 
 		if err := writeFile(adapterFile, adapterContent); err != nil {
 			return err
@@ -251,6 +356,11 @@ func generateRepositoryImplementations(moduleName string, tables []Table) error
 			return err
 		}
 		fmt.Printf("Created repository implementation: %s\n", repoFile)
+
+		testFile := filepath.Join(moduleName, "internal", "repository", "implementor", "postgres", strings.ToLower(table.Name)+"_repo_test.go")
+		if err := writeFile(testFile, generatePostgresRepositoryTest(moduleName, table)); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -267,7 +377,7 @@ func generateRestAPI(moduleName string, tables []Table) error {
 	fmt.Printf("Created REST API: %s\n", restFile)
 
 	// Generate REST parameter file
-	parameterContent := generateRestParameter(tables)
+	parameterContent := generateRestParameter(moduleName, tables)
 	parameterFile := filepath.Join(moduleName, "internal", "interactor", "rest", "rest_parameter.go")
 
 	if err := writeFile(parameterFile, parameterContent); err != nil {
@@ -275,6 +385,165 @@ func generateRestAPI(moduleName string, tables []Table) error {
 	}
 	fmt.Printf("Created REST parameters: %s\n", parameterFile)
 
+	// Generate REST query parsing for filters and sorting
+	queryContent := generateRestQuery(moduleName)
+	queryFile := filepath.Join(moduleName, "internal", "interactor", "rest", "rest_query.go")
+
+	if err := writeFile(queryFile, queryContent); err != nil {
+		return err
+	}
+	fmt.Printf("Created REST query parsing: %s\n", queryFile)
+
+	queryTestFile := filepath.Join(moduleName, "internal", "interactor", "rest", "query_test.go")
+	if err := writeFile(queryTestFile, mustProcessTemplate("rest-query-test", map[string]string{"module_name": moduleName})); err != nil {
+		return err
+	}
+	fmt.Printf("Created REST query parsing tests: %s\n", queryTestFile)
+
+	// Generate REST error responses
+	errorsContent := generateRestErrors(moduleName)
+	errorsFile := filepath.Join(moduleName, "internal", "interactor", "rest", "rest_errors.go")
+
+	if err := writeFile(errorsFile, errorsContent); err != nil {
+		return err
+	}
+	fmt.Printf("Created REST error responses: %s\n", errorsFile)
+
+	errorsTestFile := filepath.Join(moduleName, "internal", "interactor", "rest", "errors_test.go")
+	if err := writeFile(errorsTestFile, mustProcessTemplate("rest-errors-test", map[string]string{"module_name": moduleName})); err != nil {
+		return err
+	}
+	fmt.Printf("Created REST error response tests: %s\n", errorsTestFile)
+
+	// Handlers are checked against the first table; they all hand the request context down alike
+	names := namesFor(tables[0])
+	contextTestFile := filepath.Join(moduleName, "internal", "interactor", "rest", "context_test.go")
+	if err := writeFile(contextTestFile, mustProcessTemplate("rest-context-test", map[string]string{
+		"module_name":   moduleName,
+		"struct_name":   names.Struct,
+		"singular_name": names.Struct,
+		"entity_plural": names.EntityPlural,
+	})); err != nil {
+		return err
+	}
+	fmt.Printf("Created REST request context tests: %s\n", contextTestFile)
+
+	// Generate REST batch operations
+	batchFile := filepath.Join(moduleName, "internal", "interactor", "rest", "rest_batch.go")
+	if err := writeFile(batchFile, generateRestBatch(moduleName)); err != nil {
+		return err
+	}
+	fmt.Printf("Created REST batch operations: %s\n", batchFile)
+
+	batchTestFile := filepath.Join(moduleName, "internal", "interactor", "rest", "batch_test.go")
+	if err := writeFile(batchTestFile, generateRestBatchTest(moduleName)); err != nil {
+		return err
+	}
+	fmt.Printf("Created REST batch tests: %s\n", batchTestFile)
+
+	// Generate REST exports and imports
+	transferFile := filepath.Join(moduleName, "internal", "interactor", "rest", "rest_transfer.go")
+	if err := writeFile(transferFile, generateRestTransfer(moduleName)); err != nil {
+		return err
+	}
+	fmt.Printf("Created REST exports and imports: %s\n", transferFile)
+
+	transferTestFile := filepath.Join(moduleName, "internal", "interactor", "rest", "transfer_test.go")
+	if err := writeFile(transferTestFile, generateRestTransferTest(moduleName)); err != nil {
+		return err
+	}
+	fmt.Printf("Created REST export and import tests: %s\n", transferTestFile)
+
+	// Generate the OpenAPI document of the routes and the page serving it
+	restDir := filepath.Join(moduleName, "internal", "interactor", "rest")
+	specFile := filepath.Join(moduleName, "openapi.yaml")
+	if err := writeFile(specFile, generateOpenAPIYAML(moduleName, tables)); err != nil {
+		return err
+	}
+	fmt.Printf("Created OpenAPI specification: %s\n", specFile)
+
+	for name, content := range map[string]string{
+		"openapi.json":    generateOpenAPIJSON(moduleName, tables),
+		"docs.html":       mustProcessTemplate("rest-docs", map[string]string{"module_name": moduleName}),
+		"rest_openapi.go": mustProcessTemplate("rest-openapi", map[string]string{}),
+		"openapi_test.go": mustProcessTemplate("rest-openapi-test", map[string]string{}),
+	} {
+		docsFile := filepath.Join(restDir, name)
+		if err := writeFile(docsFile, content); err != nil {
+			return err
+		}
+		fmt.Printf("Created REST API documentation: %s\n", docsFile)
+	}
+
+	// Generate Authenticate middleware tests
+	authTestFile := filepath.Join(moduleName, "internal", "interactor", "rest", "auth_test.go")
+	if err := writeFile(authTestFile, generateRestAuthTest(moduleName)); err != nil {
+		return err
+	}
+	fmt.Printf("Created REST authentication tests: %s\n", authTestFile)
+
+	// Generate tenant scoping
+	if usesTenancy() {
+		tenantFile := filepath.Join(moduleName, "internal", "interactor", "rest", "rest_tenant.go")
+		if err := writeFile(tenantFile, generateRestTenant(moduleName)); err != nil {
+			return err
+		}
+		fmt.Printf("Created REST tenant scoping: %s\n", tenantFile)
+
+		tenantTestFile := filepath.Join(moduleName, "internal", "interactor", "rest", "tenant_test.go")
+		if err := writeFile(tenantTestFile, generateRestTenantTest(moduleName)); err != nil {
+			return err
+		}
+		fmt.Printf("Created REST tenant scoping tests: %s\n", tenantTestFile)
+
+		for name, templateName := range map[string]string{"tenancy.go": "tenancy", "tenancy_test.go": "tenancy-test"} {
+			tenancyFile := filepath.Join(moduleName, "internal", "tenancy", name)
+			if err := writeFile(tenancyFile, mustProcessTemplate(templateName, map[string]string{})); err != nil {
+				return err
+			}
+			fmt.Printf("Created tenancy: %s\n", tenancyFile)
+		}
+	}
+
+	// Generate the listing of soft-deleted records
+	if slices.ContainsFunc(tables, usesSoftDelete) {
+		for name, templateName := range map[string]string{"rest_deleted.go": "rest-deleted", "deleted_test.go": "rest-deleted-test"} {
+			deletedFile := filepath.Join(moduleName, "internal", "interactor", "rest", name)
+			if err := writeFile(deletedFile, mustProcessTemplate(templateName, map[string]string{"module_name": moduleName})); err != nil {
+				return err
+			}
+			fmt.Printf("Created REST deleted records listing: %s\n", deletedFile)
+		}
+	}
+
+	// Generate the ETag preconditions of versioned records
+	if slices.ContainsFunc(tables, usesVersioning) {
+		for name, templateName := range map[string]string{"rest_version.go": "rest-version", "version_test.go": "rest-version-test"} {
+			versionFile := filepath.Join(moduleName, "internal", "interactor", "rest", name)
+			if err := writeFile(versionFile, mustProcessTemplate(templateName, map[string]string{"module_name": moduleName})); err != nil {
+				return err
+			}
+			fmt.Printf("Created REST version preconditions: %s\n", versionFile)
+		}
+	}
+
+	// Generate REST response helpers in the configured body format
+	responseContent := generateRestResponse(moduleName)
+	responseFile := filepath.Join(moduleName, "internal", "interactor", "rest", "rest_response.go")
+
+	if err := writeFile(responseFile, responseContent); err != nil {
+		return err
+	}
+	fmt.Printf("Created REST responses: %s\n", responseFile)
+
+	if usesProblemDetails() {
+		packageFile := filepath.Join(moduleName, "internal", "response", "response.go")
+		if err := writeFile(packageFile, generateResponsePackage(moduleName)); err != nil {
+			return err
+		}
+		fmt.Printf("Created problem details response package: %s\n", packageFile)
+	}
+
 	// Generate individual handlers for each table
 	for _, table := range tables {
 		handlerContent := generateRestHandler(moduleName, table)
@@ -289,8 +558,26 @@ func generateRestAPI(moduleName string, tables []Table) error {
 	return nil
 }
 
+// generatedFiles holds the paths written by the current run
+var generatedFiles = map[string]bool{}
+
+// claimPath records that the current run writes filePath. Two generators writing the same path,
+// e.g. a table named after a shared file, would silently overwrite each other, so that fails.
+func claimPath(filePath string) error {
+	path := filepath.Clean(filePath)
+	if generatedFiles[path] {
+		return fmt.Errorf("%s is generated twice, rename the table it is named after with tables.<table>.singular", path)
+	}
+	generatedFiles[path] = true
+	return nil
+}
+
 // writeFile writes content to a file, creating directories as needed
 func writeFile(filePath, content string) error {
+	if err := claimPath(filePath); err != nil {
+		return err
+	}
+
 	// Create directory if it doesn't exist
 	dir := filepath.Dir(filePath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -307,6 +594,10 @@ func writeFile(filePath, content string) error {
 
 // writeExecutableFile writes content to an executable file, creating directories as needed
 func writeExecutableFile(filePath, content string) error {
+	if err := claimPath(filePath); err != nil {
+		return err
+	}
+
 	// Create directory if it doesn't exist
 	dir := filepath.Dir(filePath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
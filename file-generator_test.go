@@ -0,0 +1,21 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteFileRejectsPathsGeneratedTwice(t *testing.T) {
+	generatedFiles = map[string]bool{}
+	t.Cleanup(func() { generatedFiles = map[string]bool{} })
+
+	path := filepath.Join(t.TempDir(), "dto", "batch_helpers.go")
+	if err := writeFile(path, "package dto\n"); err != nil {
+		t.Fatalf("first write: %v", err)
+	}
+	err := writeFile(filepath.Join(filepath.Dir(path), ".", "batch_helpers.go"), "package dto\n")
+	if err == nil || !strings.Contains(err.Error(), "generated twice") {
+		t.Errorf("second write error = %v, want the path to be generated twice", err)
+	}
+}
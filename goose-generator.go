@@ -19,6 +19,26 @@ func generateGooseMigration(moduleName string, tables []Table, schema string) er
 		schemaCreation.WriteString(fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS \"%s\";\n\n", schema))
 	}
 
+	// Create enum types before the tables that use them
+	var typeDrops strings.Builder
+	createdTypes := map[string]bool{}
+	for _, table := range tables {
+		for _, col := range table.Columns {
+			typeName := strings.ToLower(col.Type)
+			if len(col.EnumValues) == 0 || createdTypes[typeName] {
+				continue
+			}
+			createdTypes[typeName] = true
+
+			labels := make([]string, len(col.EnumValues))
+			for i, value := range col.EnumValues {
+				labels[i] = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+			}
+			schemaCreation.WriteString(fmt.Sprintf("CREATE TYPE %s AS ENUM (%s);\n", col.Type, strings.Join(labels, ", ")))
+			typeDrops.WriteString(fmt.Sprintf("DROP TYPE IF EXISTS %s;\n", col.Type))
+		}
+	}
+
 	// Generate table creations
 	var tableCreations strings.Builder
 	var tableDrops strings.Builder
@@ -29,6 +49,7 @@ func generateGooseMigration(moduleName string, tables []Table, schema string) er
 		// Generate CREATE TABLE statement
 		tableCreations.WriteString(generateCreateTable(table, schema))
 		tableCreations.WriteString("\n")
+		tableCreations.WriteString(generateTenantPolicy(table, schema))
 
 		// Generate DROP TABLE statement (reverse order for dependencies)
 		tableName := table.Name
@@ -42,6 +63,26 @@ func generateGooseMigration(moduleName string, tables []Table, schema string) er
 		indexDrops.WriteString(generateDropIndexes(table, schema))
 	}
 
+	if usesAPIKeys() {
+		tableCreations.WriteString(generateAPIKeyTable(schema))
+		tableCreations.WriteString("\n")
+		apiKeyTableName := apiKeyTable
+		if schema != "" {
+			apiKeyTableName = fmt.Sprintf("%s.%s", schema, apiKeyTable)
+		}
+		tableDrops.WriteString(fmt.Sprintf("DROP TABLE IF EXISTS %s;\n", apiKeyTableName))
+	}
+
+	if usesAuditLog() {
+		tableCreations.WriteString(generateAuditLogTable(schema))
+		tableCreations.WriteString("\n")
+		auditLogTableName := auditLogTable
+		if schema != "" {
+			auditLogTableName = fmt.Sprintf("%s.%s", schema, auditLogTable)
+		}
+		tableDrops.WriteString(fmt.Sprintf("DROP TABLE IF EXISTS %s;\n", auditLogTableName))
+	}
+
 	// Reverse the order of table drops for proper dependency handling
 	tableDropLines := strings.Split(strings.TrimSpace(tableDrops.String()), "\n")
 	var reversedDrops strings.Builder
@@ -54,6 +95,7 @@ func generateGooseMigration(moduleName string, tables []Table, schema string) er
 
 	// Generate schema drops
 	var schemaDrops strings.Builder
+	schemaDrops.WriteString(typeDrops.String())
 	if schema != "" {
 		schemaDrops.WriteString(fmt.Sprintf("DROP SCHEMA IF EXISTS \"%s\" CASCADE;\n", schema))
 	}
@@ -88,6 +130,36 @@ func generateGooseMigration(moduleName string, tables []Table, schema string) er
 	return nil
 }
 
+// generateTenantPolicy enables row-level security on a tenant-scoped table, so that even queries
+// that bypass the repository only see rows of the tenant in the app.tenant_id setting
+func generateTenantPolicy(table Table, schema string) string {
+	column, ok := tenantColumnFor(table)
+	if !ok {
+		return ""
+	}
+
+	tableName := table.Name
+	if schema != "" {
+		tableName = fmt.Sprintf("%s.%s", schema, table.Name)
+	}
+	castType := strings.ToUpper(column.Type)
+	switch castType {
+	case "SERIAL":
+		castType = "INTEGER"
+	case "BIGSERIAL":
+		castType = "BIGINT"
+	}
+	condition := fmt.Sprintf("%s = NULLIF(current_setting('app.tenant_id', true), '')::%s", column.Name, castType)
+
+	var sql strings.Builder
+	sql.WriteString(fmt.Sprintf("ALTER TABLE %s ENABLE ROW LEVEL SECURITY;\n", tableName))
+	sql.WriteString(fmt.Sprintf("ALTER TABLE %s FORCE ROW LEVEL SECURITY;\n", tableName))
+	sql.WriteString(fmt.Sprintf("CREATE POLICY %s_tenant_isolation ON %s\n", table.Name, tableName))
+	sql.WriteString(fmt.Sprintf("    USING (%s)\n", condition))
+	sql.WriteString(fmt.Sprintf("    WITH CHECK (%s);\n", condition))
+	return sql.String()
+}
+
 // generateCreateTable generates CREATE TABLE SQL for a single table
 func generateCreateTable(table Table, schema string) string {
 	var sql strings.Builder
@@ -104,7 +176,7 @@ func generateCreateTable(table Table, schema string) string {
 	hasCreatedAt := false
 	hasUpdatedAt := false
 	hasDeletedAt := false
-	hasIsDeleted := false
+	declaredAuditColumns := map[string]bool{}
 
 	for _, col := range table.Columns {
 		lowerName := strings.ToLower(col.Name)
@@ -117,8 +189,8 @@ func generateCreateTable(table Table, schema string) string {
 			hasUpdatedAt = true
 		case "deleted_at":
 			hasDeletedAt = true
-		case "is_deleted":
-			hasIsDeleted = true
+		case "created_by", "updated_by", "deleted_by":
+			declaredAuditColumns[lowerName] = true
 		}
 	}
 
@@ -141,18 +213,32 @@ func generateCreateTable(table Table, schema string) string {
 	if !hasUpdatedAt {
 		sql.WriteString("    updated_at TIMESTAMPTZ DEFAULT NOW(),\n")
 	}
-	if !hasDeletedAt {
+	if !hasDeletedAt && usesSoftDelete(table) {
 		sql.WriteString("    deleted_at TIMESTAMPTZ,\n")
 	}
-	if !hasIsDeleted {
-		sql.WriteString("    is_deleted BOOLEAN DEFAULT FALSE\n")
-	} else {
-		// Remove the trailing comma from the last column
-		sqlStr := sql.String()
-		sql.Reset()
-		sql.WriteString(strings.TrimSuffix(sqlStr, ",\n") + "\n")
+	if usesVersioning(table) {
+		sql.WriteString("    version BIGINT NOT NULL DEFAULT 1,\n")
+	}
+	if usesAuditColumns() {
+		for _, name := range []string{"created_by", "updated_by", "deleted_by"} {
+			if !declaredAuditColumns[name] {
+				sql.WriteString(fmt.Sprintf("    %s TEXT NOT NULL DEFAULT '',\n", name))
+			}
+		}
+	}
+
+	// Table-level constraints follow the columns they refer to
+	for _, constraint := range table.Constraints {
+		sql.WriteString("    ")
+		sql.WriteString(constraint)
+		sql.WriteString(",\n")
 	}
 
+	// Remove the trailing comma from the last definition
+	sqlStr := sql.String()
+	sql.Reset()
+	sql.WriteString(strings.TrimSuffix(sqlStr, ",\n") + "\n")
+
 	sql.WriteString(");")
 	return sql.String()
 } // generateColumnDefinition generates SQL column definition
@@ -187,6 +273,21 @@ func generateColumnDefinition(col Column, tableName string) string {
 		def.WriteString(col.DefaultValue)
 	}
 
+	if col.GeneratedExpr != "" {
+		def.WriteString(" ")
+		def.WriteString(col.GeneratedExpr)
+	}
+
+	if col.IsUnique && !col.IsPrimaryKey {
+		def.WriteString(" UNIQUE")
+	}
+
+	if col.InlineCheck != "" {
+		def.WriteString(" CHECK (")
+		def.WriteString(col.InlineCheck)
+		def.WriteString(")")
+	}
+
 	return def.String()
 }
 
@@ -289,6 +390,12 @@ func generateIndexes(table Table, schema string) string {
 		}
 	}
 
+	// Soft-deleted tables are filtered on deleted_at by every query
+	if usesSoftDelete(table) {
+		indexes.WriteString(fmt.Sprintf("CREATE INDEX IF NOT EXISTS %sidx_%s_deleted_at ON %s(deleted_at);\n",
+			schemaPrefix, table.Name, tableName))
+	}
+
 	return indexes.String()
 }
 
@@ -317,5 +424,9 @@ func generateDropIndexes(table Table, schema string) string {
 		}
 	}
 
+	if usesSoftDelete(table) {
+		drops.WriteString(fmt.Sprintf("DROP INDEX IF EXISTS %sidx_%s_deleted_at;\n", schemaPrefix, table.Name))
+	}
+
 	return drops.String()
 }
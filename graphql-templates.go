@@ -0,0 +1,842 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// graphQLRoute is the path the GraphQL endpoint is served on
+const graphQLRoute = "graphql"
+
+// graphQLTypeNames are the types every generated schema declares, which no table may take
+var graphQLTypeNames = map[string]bool{
+	"Query": true, "Mutation": true, "FilterOp": true, "Time": true, "Int64": true,
+	"ID": true, "Int": true, "Float": true, "String": true, "Boolean": true,
+}
+
+// graphQLField is a column as a field of a GraphQL object or input
+type graphQLField struct {
+	Column
+	Name   string // GraphQL field name, e.g. publishedAt
+	Method string // Go name of its resolver method and input member, e.g. PublishedAt
+	Type   string // GraphQL type without nullability, e.g. Time or [String!]
+}
+
+// graphQLRelation is an object field following a foreign key: from the referencing record to the
+// referenced one, or from the referenced record to every record referencing it
+type graphQLRelation struct {
+	Name   string
+	Method string
+	Many   bool
+	Key    graphQLField // the foreign key column
+	Child  Table        // the table of the foreign key
+	Parent Table        // the table the foreign key references
+	Doc    string
+}
+
+// loader returns the field of the per-request loaders that looks the relation up, e.g. userByID or postsByUserID
+func (r graphQLRelation) loader() string {
+	if r.Many {
+		return fmt.Sprintf("%sBy%s", namesFor(r.Child).PluralVar, r.Key.Method)
+	}
+	return namesFor(r.Parent).Var + "ByID"
+}
+
+// graphQLName returns the GraphQL name of a column, e.g. createdAt for created_at
+func graphQLName(column string) string {
+	return protoJSONName(strings.ToLower(column))
+}
+
+// graphQLFieldOf lowers the leading word of a Go name, e.g. userAddresses for UserAddresses, apiKeys for APIKeys
+// and apiURLs for APIURLs
+func graphQLFieldOf(goName string) string {
+	runes := []rune(goName)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	// A known initialism ends the leading word where it ends, e.g. API in APIURLs
+	word := upper
+	for word > 1 && !initialisms[string(runes[:word])] {
+		word--
+	}
+	if word == 1 && upper > 1 {
+		// Otherwise the last capital of an unknown initialism starts the next word
+		word = upper
+		if upper < len(runes) {
+			word--
+		}
+	}
+	// The s of a pluralized initialism belongs to it, e.g. ids for IDs
+	if word > 1 && word == upper && word < len(runes) && runes[word] == 's' &&
+		(word+1 == len(runes) || unicode.IsUpper(runes[word+1])) {
+		word++
+	}
+	return strings.ToLower(string(runes[:word])) + string(runes[word:])
+}
+
+// exported capitalizes a GraphQL name into the Go name resolvers and inputs use for it
+func exported(name string) string {
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// graphQLTypeOf returns the GraphQL type of a column. Foreign keys to tables of the schema are IDs.
+func graphQLTypeOf(col Column, tables map[string]Table) string {
+	if _, ok := referencedTable(col, tables); ok {
+		return "ID"
+	}
+	switch col.GoType {
+	case "int64":
+		return "Int64"
+	case "float64":
+		return "Float"
+	case "bool":
+		return "Boolean"
+	case "time.Time":
+		return "Time"
+	case "[]string":
+		return "[String!]"
+	default:
+		return "String"
+	}
+}
+
+// graphQLGoTypes maps the GraphQL types of columns to the Go types of resolvers and inputs
+var graphQLGoTypes = map[string]string{
+	"ID": "ID", "Int64": "Int64", "Float": "float64", "Boolean": "bool",
+	"Time": "graphql.Time", "[String!]": "[]string", "String": "string",
+}
+
+// referencedTable returns the table of the schema an integer column references by id
+func referencedTable(col Column, tables map[string]Table) (Table, bool) {
+	if col.RefColumn != "id" || col.GoType != "int64" {
+		return Table{}, false
+	}
+	table, ok := tables[col.RefTable]
+	return table, ok
+}
+
+// tablesByName indexes tables by their lowercase name, as foreign keys name them
+func tablesByName(tables []Table) map[string]Table {
+	byName := make(map[string]Table, len(tables))
+	for _, table := range tables {
+		byName[strings.ToLower(table.Name)] = table
+	}
+	return byName
+}
+
+// graphQLFields returns the table-specific fields of a table's object: the columns returned to clients
+func graphQLFields(table Table, tables map[string]Table) []graphQLField {
+	var fields []graphQLField
+	for _, col := range table.Columns {
+		if col.isMeta() || !col.inResponse() {
+			continue
+		}
+		fields = append(fields, newGraphQLField(col, tables))
+	}
+	return fields
+}
+
+func newGraphQLField(col Column, tables map[string]Table) graphQLField {
+	name := graphQLName(col.Name)
+	return graphQLField{Column: col, Name: name, Method: exported(name), Type: graphQLTypeOf(col, tables)}
+}
+
+// graphQLMetaFields lists the fields every object of a table has besides its columns
+func graphQLMetaFields(table Table) []string {
+	fields := []string{"id", "createdAt", "updatedAt"}
+	if usesVersioning(table) {
+		fields = append(fields, "version")
+	}
+	if usesSoftDelete(table) {
+		fields = append(fields, "deletedAt")
+	}
+	if usesAuditColumns() {
+		fields = append(fields, "createdBy", "updatedBy")
+	}
+	return fields
+}
+
+// graphQLRelations derives the relationship fields of every table from the foreign keys of the schema,
+// keyed by the table whose object has them. A foreign key user_id of posts gives posts a user field and
+// users a posts field; when a table references another more than once, the list fields name the key.
+func graphQLRelations(tables []Table) map[string][]graphQLRelation {
+	byName := tablesByName(tables)
+
+	// Fields of the objects are taken first, relations must not shadow them
+	taken := map[string]map[string]bool{}
+	for _, table := range tables {
+		taken[table.Name] = map[string]bool{}
+		for _, name := range graphQLMetaFields(table) {
+			taken[table.Name][strings.ToLower(name)] = true
+		}
+		for _, field := range graphQLFields(table, byName) {
+			taken[table.Name][strings.ToLower(field.Name)] = true
+		}
+	}
+	unique := func(table, name string) string {
+		candidate := name
+		for n := 2; taken[table][strings.ToLower(candidate)]; n++ {
+			candidate = fmt.Sprintf("%s%d", name, n)
+		}
+		taken[table][strings.ToLower(candidate)] = true
+		return candidate
+	}
+
+	references := map[[2]string]int{}
+	for _, child := range tables {
+		for _, field := range graphQLFields(child, byName) {
+			if parent, ok := referencedTable(field.Column, byName); ok {
+				references[[2]string{child.Name, parent.Name}]++
+			}
+		}
+	}
+
+	relations := map[string][]graphQLRelation{}
+	for _, child := range tables {
+		for _, field := range graphQLFields(child, byName) {
+			parent, ok := referencedTable(field.Column, byName)
+			if !ok {
+				continue
+			}
+			column := strings.ToLower(field.Column.Name)
+			base := strings.TrimSuffix(column, "_id")
+			if base == column || base == "" {
+				base = column + "_record"
+			}
+
+			one := unique(child.Name, graphQLName(base))
+			relations[child.Name] = append(relations[child.Name], graphQLRelation{
+				Name: one, Method: exported(one), Key: field, Child: child, Parent: parent,
+				Doc: fmt.Sprintf("The %s %s references", namesFor(parent).Entity, field.Column.Name),
+			})
+
+			many := graphQLFieldOf(namesFor(child).Plural)
+			if references[[2]string{child.Name, parent.Name}] > 1 || child.Name == parent.Name {
+				many += "By" + exported(graphQLName(base))
+			}
+			many = unique(parent.Name, many)
+			relations[parent.Name] = append(relations[parent.Name], graphQLRelation{
+				Name: many, Method: exported(many), Many: true, Key: field, Child: child, Parent: parent,
+				Doc: fmt.Sprintf("The %s whose %s references this %s", namesFor(child).EntityPlural, field.Column.Name, namesFor(parent).Entity),
+			})
+		}
+	}
+	return relations
+}
+
+// graphQLDescription writes a string description in the given indentation
+func graphQLDescription(doc, indent string) string {
+	return fmt.Sprintf("%s%q\n", indent, doc)
+}
+
+// generateGraphQLSchema renders the SDL of every table: its object with the fields of its columns and
+// relations, its list page, filter and sort inputs, and its fields of Query and Mutation
+func generateGraphQLSchema(moduleName string, tables []Table) string {
+	byName := tablesByName(tables)
+	relations := graphQLRelations(tables)
+
+	var b, query, mutation strings.Builder
+	b.WriteString(fmt.Sprintf("# Generated by boGO from the schema of %s. Regenerate the service instead of editing this file.\n\n", moduleName))
+	b.WriteString("schema {\n  query: Query\n  mutation: Mutation\n}\n\n")
+	b.WriteString(graphQLDescription("An RFC 3339 timestamp", ""))
+	b.WriteString("scalar Time\n\n")
+	b.WriteString(graphQLDescription("A 64-bit integer", ""))
+	b.WriteString("scalar Int64\n\n")
+	b.WriteString(graphQLDescription("Compares a column with a value, like ?column[op]=value on the REST list endpoints. IN and BETWEEN take comma-separated values.", ""))
+	b.WriteString("enum FilterOp {\n  EQ\n  NE\n  GT\n  GTE\n  LT\n  LTE\n  IN\n  LIKE\n  BETWEEN\n  IS_NULL\n}\n")
+
+	for _, table := range tables {
+		names := namesFor(table)
+		s := names.Struct
+		fields := graphQLFields(table, byName)
+
+		// The object of the table
+		b.WriteString("\n" + graphQLDescription(fmt.Sprintf("A %s as the API returns it", names.Entity), ""))
+		b.WriteString(fmt.Sprintf("type %s {\n  id: ID!\n", s))
+		for _, field := range fields {
+			nullability := "!"
+			if field.IsNullable {
+				nullability = ""
+			}
+			b.WriteString(fmt.Sprintf("  %s: %s%s\n", field.Name, field.Type, nullability))
+		}
+		b.WriteString("  createdAt: Time!\n  updatedAt: Time!\n")
+		if usesVersioning(table) {
+			b.WriteString("  version: Int64!\n")
+		}
+		if usesSoftDelete(table) {
+			b.WriteString("  deletedAt: Time\n")
+		}
+		if usesAuditColumns() {
+			b.WriteString("  createdBy: String\n  updatedBy: String\n")
+		}
+		for _, relation := range relations[table.Name] {
+			b.WriteString(graphQLDescription(relation.Doc, "  "))
+			if relation.Many {
+				b.WriteString(fmt.Sprintf("  %s: [%s!]\n", relation.Name, namesFor(relation.Child).Struct))
+			} else {
+				b.WriteString(fmt.Sprintf("  %s: %s\n", relation.Name, namesFor(relation.Parent).Struct))
+			}
+		}
+		b.WriteString("}\n")
+
+		// Its list page
+		b.WriteString("\n" + graphQLDescription(fmt.Sprintf("A page of %s", names.EntityPlural), ""))
+		if usesCursorPagination(table) {
+			b.WriteString(fmt.Sprintf("type %sPage {\n  items: [%s!]!\n  limit: Int!\n", s, s))
+			b.WriteString(graphQLDescription("Continues the list on the next page, null on the last page", "  "))
+			b.WriteString("  nextCursor: String\n}\n")
+		} else {
+			b.WriteString(fmt.Sprintf("type %sPage {\n  items: [%s!]!\n  total: Int64!\n  limit: Int!\n  page: Int!\n}\n", s, s))
+		}
+
+		// The columns it is filtered and sorted by
+		b.WriteString("\n" + graphQLDescription(fmt.Sprintf("The columns %s can be filtered and sorted by", names.EntityPlural), ""))
+		b.WriteString(fmt.Sprintf("enum %sColumn {\n", s))
+		for _, col := range queryColumnsFor(table) {
+			b.WriteString("  " + strings.ToUpper(col.Name) + "\n")
+		}
+		b.WriteString("}\n\n")
+		b.WriteString(graphQLDescription("Compares a column with a value, testing equality without an op", ""))
+		b.WriteString(fmt.Sprintf("input %sFilter {\n  column: %sColumn!\n  op: FilterOp\n  value: String!\n}\n", s, s))
+		if !usesCursorPagination(table) {
+			b.WriteString("\n" + graphQLDescription("Sorts by a column, ascending unless desc is true", ""))
+			b.WriteString(fmt.Sprintf("input %sSort {\n  column: %sColumn!\n  desc: Boolean\n}\n", s, s))
+		}
+
+		// Its create and update inputs
+		for _, input := range []struct {
+			name, doc string
+			include   func(Column) bool
+			required  func(Column) bool
+		}{
+			{"Create" + s + "Input", "The members of a new " + names.Entity, Column.inCreateRequest, Column.requiresValue},
+			{"Update" + s + "Input", "The members replacing those of a " + names.Entity, Column.inUpdateRequest, Column.requiredOnUpdate},
+		} {
+			b.WriteString("\n" + graphQLDescription(input.doc, ""))
+			b.WriteString(fmt.Sprintf("input %s {\n", input.name))
+			for _, col := range table.Columns {
+				if !input.include(col) {
+					continue
+				}
+				field := newGraphQLField(col, byName)
+				nullability := ""
+				if input.required(col) {
+					nullability = "!"
+				}
+				b.WriteString(fmt.Sprintf("  %s: %s%s\n", field.Name, field.Type, nullability))
+			}
+			b.WriteString("}\n")
+		}
+
+		// Its fields of Query and Mutation
+		query.WriteString(graphQLDescription(fmt.Sprintf("A %s by id, null when there is none", names.Entity), "  "))
+		query.WriteString(fmt.Sprintf("  %s(id: ID!): %s\n", graphQLFieldOf(s), s))
+		includeDeleted := ""
+		if usesSoftDelete(table) {
+			includeDeleted = ", includeDeleted: Boolean"
+		}
+		query.WriteString(graphQLDescription(fmt.Sprintf("A page of %s, filtered and sorted like GET /%s", names.EntityPlural, names.EntityPlural), "  "))
+		if usesCursorPagination(table) {
+			query.WriteString(fmt.Sprintf("  %s(filter: [%sFilter!], limit: Int, after: String%s): %sPage!\n", graphQLFieldOf(names.Plural), s, includeDeleted, s))
+		} else {
+			query.WriteString(fmt.Sprintf("  %s(filter: [%sFilter!], sort: [%sSort!], limit: Int, offset: Int%s): %sPage!\n", graphQLFieldOf(names.Plural), s, s, includeDeleted, s))
+		}
+
+		version := ""
+		if usesVersioning(table) {
+			version = ", version: Int64!"
+		}
+		mutation.WriteString(graphQLDescription(fmt.Sprintf("Creates a %s and returns it", names.Entity), "  "))
+		mutation.WriteString(fmt.Sprintf("  create%s(input: Create%sInput!): %s!\n", s, s, s))
+		mutation.WriteString(graphQLDescription(fmt.Sprintf("Replaces a %s and returns it", names.Entity), "  "))
+		mutation.WriteString(fmt.Sprintf("  update%s(id: ID!, input: Update%sInput!%s): %s!\n", s, s, version, s))
+		mutation.WriteString(graphQLDescription(fmt.Sprintf("Deletes a %s and returns its id", names.Entity), "  "))
+		mutation.WriteString(fmt.Sprintf("  delete%s(id: ID!%s): ID!\n", s, version))
+	}
+
+	b.WriteString("\ntype Query {\n" + query.String() + "}\n")
+	b.WriteString("\ntype Mutation {\n" + mutation.String() + "}\n")
+	return b.String()
+}
+
+// graphQLResponseMethod returns the resolver method of a column's field. Nullable columns resolve
+// to null at the zero value, which the DTOs leave unset columns at.
+func graphQLResponseMethod(receiver string, field graphQLField) string {
+	goType := graphQLGoTypes[field.Type]
+	value := "r.record." + field.FieldName
+	switch field.Type {
+	case "ID", "Int64":
+		value = fmt.Sprintf("%s(%s)", goType, value)
+	case "Time":
+		value = fmt.Sprintf("graphql.Time{Time: %s}", value)
+	}
+	if field.IsNullable {
+		goType = "*" + goType
+		switch field.Type {
+		case "Time":
+			value = fmt.Sprintf("optionalTime(r.record.%s)", field.FieldName)
+		case "[String!]":
+			value = fmt.Sprintf("optionalList(r.record.%s)", field.FieldName)
+		default:
+			value = fmt.Sprintf("optional(%s)", value)
+		}
+	}
+	return fmt.Sprintf("\nfunc (r *%s) %s() %s {\n\treturn %s\n}\n", receiver, field.Method, goType, value)
+}
+
+// graphQLInputType returns the Go type of an input member, a pointer unless the input requires it
+func graphQLInputType(field graphQLField, required bool) string {
+	if required {
+		return graphQLGoTypes[field.Type]
+	}
+	return "*" + graphQLGoTypes[field.Type]
+}
+
+// graphQLRequestValue returns the expression reading a column from an input into a request DTO field
+// of the given type. required tells whether the input member is non-null.
+func graphQLRequestValue(field graphQLField, requestType string, required bool) string {
+	in := "in." + field.Method
+	integer := field.Type == "ID" || field.Type == "Int64"
+	switch {
+	case !required && strings.HasPrefix(requestType, "*") && integer:
+		return fmt.Sprintf("optionalInt64(%s)", in)
+	case !required && strings.HasPrefix(requestType, "*") && field.Type == "Time":
+		return fmt.Sprintf("optionalTimeOf(%s)", in)
+	case !required && strings.HasPrefix(requestType, "*"):
+		return in
+	case strings.HasPrefix(requestType, "*") && integer:
+		return fmt.Sprintf("ptr(int64(%s))", in)
+	case strings.HasPrefix(requestType, "*"):
+		return fmt.Sprintf("ptr(%s)", in)
+	case required && field.Type == "Time":
+		return in + ".Time"
+	case required:
+		return in
+	case integer:
+		return fmt.Sprintf("int64(value(%s))", in)
+	case field.Type == "Time":
+		return fmt.Sprintf("value(%s).Time", in)
+	default:
+		return fmt.Sprintf("value(%s)", in)
+	}
+}
+
+// generateGraphQLResolver renders the root resolver over every table's service, with the per-request
+// loaders of the relations and the functions fetching their batches
+func generateGraphQLResolver(moduleName string, tables []Table) string {
+	relations := graphQLRelations(tables)
+
+	var serviceFields, serviceInit, loaderFields, loaderInit, fetchers strings.Builder
+	serviceParams := make([]string, len(tables))
+	for i, table := range tables {
+		names := namesFor(table)
+		service := names.Entity + "Service"
+		serviceFields.WriteString(fmt.Sprintf("\t%s interactor.I%sService\n", service, names.Struct))
+		serviceInit.WriteString(fmt.Sprintf("\t\t%s: %s,\n", service, service))
+		serviceParams[i] = fmt.Sprintf("%s interactor.I%sService", service, names.Struct)
+	}
+
+	// Every table referenced by a foreign key has a loader by id, every foreign key one by its column
+	seen := map[string]bool{}
+	for _, table := range tables {
+		for _, relation := range relations[table.Name] {
+			if seen[relation.loader()] {
+				continue
+			}
+			seen[relation.loader()] = true
+			vars := map[string]string{
+				"loader":      relation.loader(),
+				"struct_name": namesFor(relation.Parent).Struct,
+				"plural_name": namesFor(relation.Parent).Plural,
+				"service":     namesFor(relation.Parent).Entity + "Service",
+				"entity":      namesFor(relation.Parent).EntityPlural,
+				"column":      relation.Key.Column.Name,
+			}
+			valueType := "dto." + vars["struct_name"] + "Response"
+			templateName := "graphql-fetch-by-id"
+			if relation.Many {
+				vars["struct_name"] = namesFor(relation.Child).Struct
+				vars["plural_name"] = namesFor(relation.Child).Plural
+				vars["service"] = namesFor(relation.Child).Entity + "Service"
+				vars["entity"] = namesFor(relation.Child).EntityPlural
+				vars["key_field"] = relation.Key.FieldName
+				valueType = "dto." + vars["plural_name"]
+				templateName = "graphql-fetch-by-key"
+			}
+			vars["fetch"] = "fetch" + exported(relation.loader())
+			loaderFields.WriteString(fmt.Sprintf("\t%s *loader[int64, %s]\n", relation.loader(), valueType))
+			loaderInit.WriteString(fmt.Sprintf("\t\t%s: newLoader(r.%s),\n", relation.loader(), vars["fetch"]))
+			fetchers.WriteString(mustProcessTemplate(templateName, vars))
+		}
+	}
+
+	vars := map[string]string{
+		"module_name":    moduleName,
+		"service_fields": serviceFields.String(),
+		"service_init":   serviceInit.String(),
+		"service_params": strings.Join(serviceParams, ", "),
+		"loader_fields":  loaderFields.String(),
+		"loader_init":    loaderInit.String(),
+		"fetchers":       fetchers.String(),
+		"tenancy_import": "",
+		"policy_fields":  "",
+		"policy_doc":     "",
+		"tenant_check":   "",
+	}
+	if usesTenancy() {
+		vars["tenancy_import"] = fmt.Sprintf("\n\t\"%s/internal/tenancy\"", moduleName)
+		vars["policy_fields"] = "\n\ttenant bool"
+		vars["policy_doc"] = ".\n// Operations on tables with a tenant column require the request to name a tenant."
+		vars["tenant_check"] = "\n\tif p.tenant {\n\t\tif _, ok := tenancy.FromContext(ctx); !ok {\n\t\t\treturn errs.Forbidden(\"request does not name a tenant\")\n\t\t}\n\t}"
+	}
+	return mustProcessTemplate("graphql-resolver", vars)
+}
+
+// generateGraphQLResolverEntity renders the object resolver of a table, its fields of Query and
+// Mutation and the conversion of its inputs
+func generateGraphQLResolverEntity(moduleName string, table Table, tables []Table) string {
+	names := namesFor(table)
+	byName := tablesByName(tables)
+	receiver := names.Entity + "Resolver"
+
+	var queryColumns strings.Builder
+	for _, col := range queryColumnsFor(table) {
+		queryColumns.WriteString(fmt.Sprintf("\n\t{QueryKey: %q, DBKey: %q, Kind: reflect.%s},", strings.ToUpper(col.Name), col.Name, col.Kind))
+	}
+
+	var fieldMethods strings.Builder
+	for _, field := range graphQLFields(table, byName) {
+		fieldMethods.WriteString(graphQLResponseMethod(receiver, field))
+	}
+
+	var createFields, updateFields, createMembers, updateMembers strings.Builder
+	for _, col := range table.Columns {
+		field := newGraphQLField(col, byName)
+		if col.inCreateRequest() {
+			createFields.WriteString(fmt.Sprintf("\n\t%s %s", field.Method, graphQLInputType(field, col.requiresValue())))
+			createMembers.WriteString(fmt.Sprintf("\n\t\t%s: %s,", col.FieldName, graphQLRequestValue(field, col.createType(), col.requiresValue())))
+		}
+		if col.inUpdateRequest() {
+			updateFields.WriteString(fmt.Sprintf("\n\t%s %s", field.Method, graphQLInputType(field, col.requiredOnUpdate())))
+			updateMembers.WriteString(fmt.Sprintf("\n\t\t%s: %s,", col.FieldName, graphQLRequestValue(field, col.updateType(), col.requiredOnUpdate())))
+		}
+	}
+
+	// Relations are queued for batched lookups as soon as records are wrapped into resolvers
+	var queue, relationMethods strings.Builder
+	for _, relation := range graphQLRelations(tables)[table.Name] {
+		key := "record.ID"
+		if !relation.Many {
+			key = "record." + relation.Key.FieldName
+		}
+		if relation.Key.IsNullable && !relation.Many {
+			queue.WriteString(fmt.Sprintf("\n\t\tif %s != 0 {\n\t\t\tl.%s.want(%s)\n\t\t}", key, relation.loader(), key))
+		} else {
+			queue.WriteString(fmt.Sprintf("\n\t\tl.%s.want(%s)", relation.loader(), key))
+		}
+
+		templateName := "graphql-relation-one"
+		target := relation.Parent
+		if relation.Many {
+			templateName = "graphql-relation-many"
+			target = relation.Child
+		}
+		nullCheck := ""
+		if relation.Key.IsNullable && !relation.Many {
+			nullCheck = fmt.Sprintf("\n\tif r.record.%s == 0 {\n\t\treturn nil, nil\n\t}", relation.Key.FieldName)
+		}
+		relationMethods.WriteString(mustProcessTemplate(templateName, map[string]string{
+			"receiver":      receiver,
+			"method":        relation.Method,
+			"doc":           strings.ToLower(relation.Doc[:1]) + relation.Doc[1:],
+			"target_struct": namesFor(target).Struct,
+			"target_entity": namesFor(target).Entity,
+			"loader":        relation.loader(),
+			"key":           "r." + key,
+			"null_check":    nullCheck,
+		}))
+	}
+	queueRelations := ""
+	if queue.Len() > 0 {
+		queueRelations = fmt.Sprintf("\n\tl := loadersFrom(ctx)\n\tfor _, record := range records {%s\n\t}", queue.String())
+	}
+
+	tenant := ""
+	if _, ok := tenantColumnFor(table); ok {
+		tenant = ", tenant: true"
+	}
+	access := accessFor(table)
+
+	vars := map[string]string{
+		"module_name":          moduleName,
+		"struct_name":          names.Struct,
+		"plural_name":          names.Plural,
+		"entity_name":          names.Entity,
+		"entity_plural":        names.EntityPlural,
+		"receiver":             receiver,
+		"query_columns":        queryColumns.String(),
+		"read_policy":          fmt.Sprintf("policy{roles: []string{%s}%s}", quotedList(access.Read), tenant),
+		"write_policy":         fmt.Sprintf("policy{roles: []string{%s}%s}", quotedList(access.Write), tenant),
+		"delete_policy":        fmt.Sprintf("policy{roles: []string{%s}%s}", quotedList(access.Delete), tenant),
+		"field_methods":        fieldMethods.String(),
+		"meta_methods":         "",
+		"relation_methods":     relationMethods.String(),
+		"queue_relations":      queueRelations,
+		"create_input_fields":  createFields.String(),
+		"update_input_fields":  updateFields.String(),
+		"create_members":       createMembers.String(),
+		"update_members":       updateMembers.String(),
+		"include_deleted_arg":  "",
+		"include_deleted":      "",
+		"version_arg":          "",
+		"update_version_check": "",
+		"delete_version_check": "",
+	}
+
+	var meta strings.Builder
+	if usesVersioning(table) {
+		meta.WriteString(fmt.Sprintf("\nfunc (r *%s) Version() Int64 {\n\treturn Int64(r.record.Version)\n}\n", receiver))
+	}
+	if usesSoftDelete(table) {
+		meta.WriteString(fmt.Sprintf("\nfunc (r *%s) DeletedAt() *graphql.Time {\n\tif r.record.DeletedAt == nil {\n\t\treturn nil\n\t}\n\treturn &graphql.Time{Time: *r.record.DeletedAt}\n}\n", receiver))
+	}
+	if usesAuditColumns() {
+		meta.WriteString(fmt.Sprintf("\nfunc (r *%s) CreatedBy() *string {\n\treturn optional(r.record.CreatedBy)\n}\n", receiver))
+		meta.WriteString(fmt.Sprintf("\nfunc (r *%s) UpdatedBy() *string {\n\treturn optional(r.record.UpdatedBy)\n}\n", receiver))
+	}
+	vars["meta_methods"] = meta.String()
+
+	// Administrators may add soft-deleted records to listings
+	if usesSoftDelete(table) {
+		vars["include_deleted_arg"] = "\n\tIncludeDeleted *bool"
+		vars["include_deleted"] = fmt.Sprintf("\n\tif value(args.IncludeDeleted) {\n\t\tif ctx, err = includeDeleted(ctx, []string{%s}); err != nil {\n\t\t\treturn nil, err\n\t\t}\n\t}", quotedList(adminRolesFor(table)))
+	}
+	// Changes to versioned records must name the version they replace
+	if usesVersioning(table) {
+		vars["version_arg"] = "\n\tVersion Int64"
+		check := "\n\tctx, err := expectVersion(ctx, args.Version)\n\tif err != nil {\n\t\treturn %s, err\n\t}\n"
+		vars["update_version_check"] = fmt.Sprintf(check, "nil")
+		vars["delete_version_check"] = fmt.Sprintf(check, "0")
+	}
+
+	listTemplate := "graphql-list"
+	if usesCursorPagination(table) {
+		listTemplate = "graphql-list-cursor"
+	}
+	vars["list_method"] = mustProcessTemplate(listTemplate, vars)
+	return mustProcessTemplate("graphql-resolver-entity", vars)
+}
+
+// generateGraphQLHandler renders the HTTP handler of the endpoint, authenticating like the REST API
+func generateGraphQLHandler(moduleName string, tables []Table) string {
+	serviceArgs := make([]string, len(tables))
+	serviceParams := make([]string, len(tables))
+	for i, table := range tables {
+		names := namesFor(table)
+		serviceArgs[i] = names.Entity + "Service"
+		serviceParams[i] = fmt.Sprintf("%sService interactor.I%sService", names.Entity, names.Struct)
+	}
+
+	vars := map[string]string{
+		"module_name":    moduleName,
+		"max_depth":      fmt.Sprint(graphQLMaxDepth()),
+		"service_params": strings.Join(serviceParams, ", "),
+		"service_args":   strings.Join(serviceArgs, ", "),
+		"auth_params":    "",
+		"auth_fields":    "",
+		"auth_init":      "",
+		"api_key_check":  "",
+		"api_key_doc":    "",
+		"tenancy_import": "",
+		"tenant_scope":   "",
+		"tenant_doc":     "",
+		"tenant_func":    "",
+	}
+
+	// API keys in X-API-Key take precedence over bearer tokens, as on the REST API
+	if usesAPIKeys() {
+		vars["auth_params"] = "apiKeys *auth.APIKeyVerifier, "
+		vars["auth_fields"] = "\n\tapiKeys  *auth.APIKeyVerifier"
+		vars["auth_init"] = ", apiKeys: apiKeys"
+		vars["api_key_doc"] = ", or the API key of the X-API-Key header"
+		vars["api_key_check"] = "\n\tif key := req.Header.Get(\"X-API-Key\"); key != \"\" {\n\t\treturn h.apiKeys.Verify(req.Context(), key)\n\t}\n"
+	}
+
+	if usesTenancy() {
+		var source, lookup string
+		switch tenantSource() {
+		case tenantSourceHeader:
+			source = "the " + tenantHeader() + " header"
+			lookup = fmt.Sprintf("\ttenant := req.Header.Get(%q)", tenantHeader())
+		case tenantSourceSubdomain:
+			source = "the subdomain of the request"
+			lookup = "\ttenant := tenancy.Subdomain(req.Host)"
+		default:
+			source = "the tenant claim of the principal"
+			lookup = "\ttenant := principal.Tenant"
+		}
+		vars["tenancy_import"] = fmt.Sprintf("\n\t\"%s/internal/tenancy\"", moduleName)
+		vars["tenant_doc"] = "\n// Requests naming a tenant are scoped to it."
+		vars["tenant_scope"] = "\n\tif ctx, err = withTenant(ctx, req, principal); err != nil {\n\t\twriteErrors(w, http.StatusForbidden, err)\n\t\treturn\n\t}"
+		vars["tenant_func"] = "\n" + mustProcessTemplate("graphql-tenant", map[string]string{
+			"tenant_source": source,
+			"tenant_lookup": lookup,
+		})
+	}
+	return mustProcessTemplate("graphql-handler", vars)
+}
+
+// generateGraphQLTest renders the tests of the endpoint over fake services
+func generateGraphQLTest(moduleName string, tables []Table) string {
+	var entityTests strings.Builder
+	handlerArgs := make([]string, 0, len(tables)+1)
+	if usesAPIKeys() {
+		handlerArgs = append(handlerArgs, "nil")
+	}
+
+	var roles []string
+	for _, table := range tables {
+		names := namesFor(table)
+		entityTests.WriteString(generateGraphQLTestEntity(table))
+		handlerArgs = append(handlerArgs, "fakes."+names.Struct)
+
+		access := accessFor(table)
+		for _, group := range [][]string{access.Read, access.Write, access.Delete, adminRolesFor(table), access.OwnerBypass} {
+			roles = append(roles, group...)
+		}
+	}
+	slices.Sort(roles)
+
+	first := namesFor(tables[0])
+	vars := map[string]string{
+		"module_name":         moduleName,
+		"entity_tests":        entityTests.String(),
+		"handler_args":        strings.Join(handlerArgs, ", "),
+		"roles":               quotedList(slices.Compact(roles)),
+		"tenant_claim_config": "",
+		"tenant_claim":        "",
+		"tenant_request":      "",
+		"first_get":           graphQLFieldOf(first.Struct),
+		"first_list":          graphQLFieldOf(first.Plural),
+		"relation_test":       generateGraphQLRelationTest(tables),
+		"max_depth":           fmt.Sprint(graphQLMaxDepth()),
+	}
+	if usesTenancy() {
+		switch tenantSource() {
+		case tenantSourceHeader:
+			vars["tenant_request"] = fmt.Sprintf("\n\treq.Header.Set(%q, \"acme\")", tenantHeader())
+		case tenantSourceSubdomain:
+			vars["tenant_request"] = "\n\treq.Host = \"acme.api.example.com\""
+		default:
+			vars["tenant_claim_config"] = fmt.Sprintf(", TenantClaim: %q", tenantClaim())
+			vars["tenant_claim"] = fmt.Sprintf("\n\t\t%q: \"acme\",", tenantClaim())
+		}
+	}
+	return mustProcessTemplate("graphql-test", vars)
+}
+
+// generateGraphQLTestEntity renders the test of the fields of Query and Mutation of a table
+func generateGraphQLTestEntity(table Table) string {
+	names := namesFor(table)
+
+	// Replacing a record takes every NOT NULL member, creating it only those without a default
+	var input, update []string
+	for _, col := range table.Columns {
+		member := fmt.Sprintf("%q: %s", graphQLName(col.Name), sampleLiteral(col))
+		if col.inCreateRequest() && col.requiresValue() {
+			input = append(input, member)
+		}
+		if col.inUpdateRequest() && col.requiredOnUpdate() {
+			update = append(update, member)
+		}
+	}
+
+	vars := map[string]string{
+		"struct_name":   names.Struct,
+		"entity_name":   names.Entity,
+		"get_field":     graphQLFieldOf(names.Struct),
+		"list_field":    graphQLFieldOf(names.Plural),
+		"input":         strings.Join(input, ", "),
+		"update_input":  strings.Join(update, ", "),
+		"version_param": "",
+		"version":       "",
+		"version_check": "",
+	}
+	if usesVersioning(table) {
+		vars["version_param"] = ", $version: Int64!"
+		vars["version"] = ", version: $version"
+		vars["version_check"] = fmt.Sprintf(`	unversioned := execute(t, h, token, `+"`"+`mutation($id: ID!) { delete%[1]s(id: $id, version: 0) }`+"`"+`, map[string]any{"id": id})
+	if code := errorCode(unversioned); code != "BAD_USER_INPUT" {
+		t.Errorf("delete%[1]s without a version error code = %%q, want BAD_USER_INPUT", code)
+	}
+`, names.Struct)
+	}
+	return mustProcessTemplate("graphql-test-entity", vars)
+}
+
+// generateGraphQLRelationTest renders the test that a relation of a list is looked up in one batch,
+// for the first foreign key of the schema
+func generateGraphQLRelationTest(tables []Table) string {
+	relations := graphQLRelations(tables)
+	for _, table := range tables {
+		for _, one := range relations[table.Name] {
+			if one.Many || one.Child.Name == one.Parent.Name {
+				continue
+			}
+			var many graphQLRelation
+			for _, relation := range relations[one.Parent.Name] {
+				if relation.Many && relation.Key.Column.Name == one.Key.Column.Name && relation.Child.Name == one.Child.Name {
+					many = relation
+				}
+			}
+			child, parent := namesFor(one.Child), namesFor(one.Parent)
+			return mustProcessTemplate("graphql-test-relation", map[string]string{
+				"child_struct":  child.Struct,
+				"parent_struct": parent.Struct,
+				"child_list":    graphQLFieldOf(child.Plural),
+				"parent_list":   graphQLFieldOf(parent.Plural),
+				"one_field":     one.Name,
+				"many_field":    many.Name,
+				"key_member":    strings.ToLower(one.Key.Column.Name),
+				"child_plural":  child.EntityPlural,
+			})
+		}
+	}
+	return ""
+}
+
+// generateGraphQL creates the GraphQL endpoint in internal/interactor/graphql: the schema of every
+// table, its resolvers over the interactor services and their tests. They are rewritten on every
+// run, so they follow the schema.
+func generateGraphQL(moduleName string, tables []Table) error {
+	graphqlDir := filepath.Join(moduleName, "internal", "interactor", "graphql")
+	vars := map[string]string{"module_name": moduleName}
+
+	files := map[string]string{
+		filepath.Join(graphqlDir, "schema.graphql"):  generateGraphQLSchema(moduleName, tables),
+		filepath.Join(graphqlDir, "handler.go"):      generateGraphQLHandler(moduleName, tables),
+		filepath.Join(graphqlDir, "resolver.go"):     generateGraphQLResolver(moduleName, tables),
+		filepath.Join(graphqlDir, "errors.go"):       mustProcessTemplate("graphql-errors", vars),
+		filepath.Join(graphqlDir, "errors_test.go"):  mustProcessTemplate("graphql-errors-test", vars),
+		filepath.Join(graphqlDir, "loader.go"):       mustProcessTemplate("graphql-loader", vars),
+		filepath.Join(graphqlDir, "query.go"):        mustProcessTemplate("graphql-query", vars),
+		filepath.Join(graphqlDir, "handler_test.go"): generateGraphQLTest(moduleName, tables),
+	}
+	for _, table := range tables {
+		entity := namesFor(table).Entity
+		files[filepath.Join(graphqlDir, entity+"_resolver.go")] = generateGraphQLResolverEntity(moduleName, table, tables)
+	}
+
+	for filePath, content := range files {
+		if err := writeFile(filePath, content); err != nil {
+			return err
+		}
+		fmt.Printf("Created GraphQL file: %s\n", filePath)
+	}
+	return nil
+}
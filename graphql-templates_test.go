@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestGraphQLFieldOf(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"User", "user"},
+		{"UserAddresses", "userAddresses"},
+		{"APIKeys", "apiKeys"},
+		{"IDs", "ids"},
+		{"APIURLs", "apiURLs"},
+		{"ID", "id"},
+		{"URLPath", "urlPath"},
+		{"HTTPSURL", "httpsURL"},
+		{"UserID", "userID"},
+		{"XYZThings", "xyzThings"},
+		{"IDsByOwner", "idsByOwner"},
+	}
+	for _, tt := range tests {
+		if got := graphQLFieldOf(tt.name); got != tt.want {
+			t.Errorf("graphQLFieldOf(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,1115 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// protoScalar describes a proto3 scalar type: its descriptor type, its wire type and its Go type
+type protoScalar struct {
+	Kind   int
+	Wire   string
+	GoType string
+	Zero   string
+}
+
+// protoScalars lists the scalar types the generated messages use
+var protoScalars = map[string]protoScalar{
+	"double": {Kind: 1, Wire: "fixed64", GoType: "float64", Zero: "0"},
+	"int64":  {Kind: 3, Wire: "varint", GoType: "int64", Zero: "0"},
+	"int32":  {Kind: 5, Wire: "varint", GoType: "int32", Zero: "0"},
+	"bool":   {Kind: 8, Wire: "varint", GoType: "bool", Zero: "false"},
+	"string": {Kind: 9, Wire: "bytes", GoType: "string", Zero: `""`},
+}
+
+// protoMessageKind is the descriptor type of message fields
+const protoMessageKind = 11
+
+// protoTimestamp is the well-known type timestamps are sent as
+const protoTimestamp = "google.protobuf.Timestamp"
+
+// protoWellKnown maps the well-known types the messages use to their file and Go type
+var protoWellKnown = map[string]struct{ File, GoType, Import string }{
+	protoTimestamp: {File: "google/protobuf/timestamp.proto", GoType: "timestamppb.Timestamp", Import: `timestamppb "google.golang.org/protobuf/types/known/timestamppb"`},
+}
+
+// protoField is a field of a generated message
+type protoField struct {
+	Name   string
+	Number int
+	// Type is a scalar type, a message of the package or a qualified well-known type
+	Type     string
+	Repeated bool
+	// Optional tracks the presence of the field with a proto3 optional
+	Optional bool
+	Doc      string
+}
+
+// protoMessage is a generated message
+type protoMessage struct {
+	Name   string
+	Doc    string
+	Fields []protoField
+	// Reserved are the numbers of fields earlier runs generated and the schema no longer has
+	Reserved []int
+}
+
+// add appends a field, numbered after the previous ones
+func (m *protoMessage) add(field protoField) {
+	field.Number = len(m.Fields) + 1
+	m.Fields = append(m.Fields, field)
+}
+
+// protoNumbers are the field numbers a .proto file gave the fields of a message, and the numbers it reserved
+type protoNumbers struct {
+	Fields   map[string]int
+	Reserved []int
+}
+
+// protoFieldLine and protoReservedLine match the field and reserved lines render writes
+var (
+	protoFieldLine    = regexp.MustCompile(`^  (?:optional |repeated )?[\w.]+ (\w+) = (\d+);$`)
+	protoReservedLine = regexp.MustCompile(`^  reserved ([\d, ]+);$`)
+)
+
+// readProtoNumbers reads the field numbers of the messages of a .proto file an earlier run generated.
+// There are none if the file does not exist yet.
+func readProtoNumbers(path string) (map[string]protoNumbers, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	messages := map[string]protoNumbers{}
+	message := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if name, ok := strings.CutPrefix(line, "message "); ok {
+			message = strings.TrimSuffix(name, " {")
+			messages[message] = protoNumbers{Fields: map[string]int{}}
+			continue
+		}
+		if message == "" {
+			continue
+		}
+		numbers := messages[message]
+		if m := protoFieldLine.FindStringSubmatch(line); m != nil {
+			numbers.Fields[m[1]], _ = strconv.Atoi(m[2])
+		} else if m := protoReservedLine.FindStringSubmatch(line); m != nil {
+			for _, part := range strings.Split(m[1], ",") {
+				number, _ := strconv.Atoi(strings.TrimSpace(part))
+				numbers.Reserved = append(numbers.Reserved, number)
+			}
+			messages[message] = numbers
+		} else if line == "}" {
+			message = ""
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// keepNumbers gives the fields of the file the numbers an earlier run gave them, so clients built
+// against earlier .proto files keep decoding them. New fields are numbered after every number the
+// message used before, and the numbers of removed fields are reserved rather than reused.
+func (f *protoFile) keepNumbers(previous map[string]protoNumbers) {
+	for i := range f.Messages {
+		message := &f.Messages[i]
+		numbers, ok := previous[message.Name]
+		if !ok {
+			continue
+		}
+		next := 1
+		for _, number := range numbers.Reserved {
+			next = max(next, number+1)
+		}
+		for _, number := range numbers.Fields {
+			next = max(next, number+1)
+		}
+		present := map[string]bool{}
+		for j := range message.Fields {
+			field := &message.Fields[j]
+			present[field.Name] = true
+			if number, ok := numbers.Fields[field.Name]; ok {
+				field.Number = number
+			} else {
+				field.Number = next
+				next++
+			}
+		}
+		message.Reserved = slices.Clone(numbers.Reserved)
+		for name, number := range numbers.Fields {
+			if !present[name] {
+				message.Reserved = append(message.Reserved, number)
+			}
+		}
+		slices.Sort(message.Reserved)
+	}
+}
+
+// protoMethod is a unary RPC of a generated service
+type protoMethod struct {
+	Name   string
+	Input  string
+	Output string
+	Doc    string
+}
+
+// protoFile is a generated .proto file with at most one service
+type protoFile struct {
+	// Path is relative to the proto directory, e.g. demo_svc/v1/user.proto
+	Path       string
+	Imports    []string
+	Messages   []protoMessage
+	Service    string
+	ServiceDoc string
+	Methods    []protoMethod
+}
+
+// protoPackageName returns the proto package of a module, e.g. demo_svc.v1 for demo-svc
+func protoPackageName(moduleName string) string {
+	name := strings.ToLower(filepath.Base(moduleName))
+	name = regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "svc_" + name
+	}
+	return name + ".v1"
+}
+
+// protoDir returns the directory of the module's .proto files below proto/, e.g. demo_svc/v1
+func protoDir(moduleName string) string {
+	return strings.ReplaceAll(protoPackageName(moduleName), ".", "/")
+}
+
+// protoGoPackage returns the import path of the generated stubs
+func protoGoPackage(moduleName string) string {
+	return moduleName + "/internal/interactor/grpc/pb"
+}
+
+// protoTypeOf returns the proto type of a Go column type; lists are repeated strings
+func protoTypeOf(goType string) string {
+	switch goType {
+	case "int64":
+		return "int64"
+	case "float64":
+		return "double"
+	case "bool":
+		return "bool"
+	case "time.Time":
+		return protoTimestamp
+	default:
+		return "string"
+	}
+}
+
+// protoColumnField returns the field of a column given its type in the request DTO, empty in responses.
+// Scalars the DTO holds as pointers are optional fields, so a missing value can be told apart from zero.
+func protoColumnField(col Column, requestType string) protoField {
+	return protoField{
+		Name:     strings.ToLower(col.Name),
+		Type:     protoTypeOf(col.GoType),
+		Repeated: col.GoType == "[]string",
+		Optional: strings.HasPrefix(requestType, "*") && col.GoType != "time.Time",
+	}
+}
+
+// protoCommonFile describes common.proto, the list messages every service shares
+func protoCommonFile(moduleName string) protoFile {
+	filter := protoMessage{Name: "ColumnFilter", Doc: "ColumnFilter compares a column with a value, like ?column[op]=value does on the REST list endpoints.\nThe in and between operators take comma-separated values, and an empty op tests equality."}
+	filter.add(protoField{Name: "column", Type: "string"})
+	filter.add(protoField{Name: "op", Type: "string"})
+	filter.add(protoField{Name: "value", Type: "string"})
+
+	page := protoMessage{Name: "PageMeta", Doc: "PageMeta describes a page of an offset-paginated list"}
+	page.add(protoField{Name: "total", Type: "int64"})
+	page.add(protoField{Name: "limit", Type: "int64"})
+	page.add(protoField{Name: "page", Type: "int64"})
+
+	cursor := protoMessage{Name: "CursorMeta", Doc: "CursorMeta describes a page of a keyset-paginated list. next_cursor is empty on the last page."}
+	cursor.add(protoField{Name: "limit", Type: "int32"})
+	cursor.add(protoField{Name: "cursor", Type: "string"})
+	cursor.add(protoField{Name: "next_cursor", Type: "string"})
+
+	return protoFile{
+		Path:     protoDir(moduleName) + "/common.proto",
+		Messages: []protoMessage{filter, page, cursor},
+	}
+}
+
+// protoEntityFile describes the .proto file of a table: its record, the requests and responses of
+// its RPCs and its service
+func protoEntityFile(moduleName string, table Table) protoFile {
+	names := namesFor(table)
+	s, p := names.Struct, names.Plural
+
+	record := protoMessage{Name: s, Doc: fmt.Sprintf("%s is a %s as the API returns it", s, names.Entity)}
+	create := protoMessage{Name: "Create" + s + "Request", Doc: fmt.Sprintf("Create%sRequest holds the members of a new %s", s, names.Entity)}
+	update := protoMessage{Name: "Update" + s + "Request", Doc: fmt.Sprintf("Update%sRequest replaces the members of a %s", s, names.Entity)}
+	update.add(protoField{Name: "id", Type: "int64"})
+
+	record.add(protoField{Name: "id", Type: "int64"})
+	for _, col := range table.Columns {
+		if col.isMeta() {
+			continue
+		}
+		if col.inResponse() {
+			record.add(protoColumnField(col, ""))
+		}
+		if col.inCreateRequest() {
+			create.add(protoColumnField(col, col.createType()))
+		}
+		if col.inUpdateRequest() {
+			update.add(protoColumnField(col, col.updateType()))
+		}
+	}
+	record.add(protoField{Name: "created_at", Type: protoTimestamp})
+	record.add(protoField{Name: "updated_at", Type: protoTimestamp})
+	if usesVersioning(table) {
+		record.add(protoField{Name: "version", Type: "int64"})
+	}
+	if usesSoftDelete(table) {
+		record.add(protoField{Name: "deleted_at", Type: protoTimestamp})
+	}
+	if usesAuditColumns() {
+		record.add(protoField{Name: "created_by", Type: "string"})
+		record.add(protoField{Name: "updated_by", Type: "string"})
+	}
+
+	get := protoMessage{Name: "Get" + s + "Request"}
+	get.add(protoField{Name: "id", Type: "int64"})
+
+	list := protoMessage{Name: "List" + p + "Request"}
+	list.add(protoField{Name: "limit", Type: "int32", Doc: "The page size, 10 unless set and capped like the REST list endpoints"})
+	listResponse := protoMessage{Name: "List" + p + "Response"}
+	listResponse.add(protoField{Name: "items", Type: s, Repeated: true})
+	if usesCursorPagination(table) {
+		list.add(protoField{Name: "cursor", Type: "string", Doc: "Continues the list from the next_cursor of the previous page"})
+		listResponse.add(protoField{Name: "meta", Type: "CursorMeta"})
+	} else {
+		list.add(protoField{Name: "offset", Type: "int32"})
+		list.add(protoField{Name: "sort", Type: "string", Repeated: true, Doc: "Sorts by columns, descending when prefixed with \"-\""})
+		listResponse.add(protoField{Name: "meta", Type: "PageMeta"})
+	}
+	list.add(protoField{Name: "filters", Type: "ColumnFilter", Repeated: true})
+	if usesSoftDelete(table) {
+		list.add(protoField{Name: "include_deleted", Type: "bool", Doc: "Adds soft-deleted records, for principals with an admin role of the table"})
+	}
+
+	created := protoMessage{Name: "Create" + s + "Response"}
+	created.add(protoField{Name: "id", Type: "int64"})
+
+	remove := protoMessage{Name: "Delete" + s + "Request"}
+	remove.add(protoField{Name: "id", Type: "int64"})
+	if usesVersioning(table) {
+		versionDoc := "The version the change expects, as If-Match names it on the REST API"
+		update.add(protoField{Name: "version", Type: "int64", Doc: versionDoc})
+		remove.add(protoField{Name: "version", Type: "int64", Doc: versionDoc})
+	}
+	removed := protoMessage{Name: "Delete" + s + "Response"}
+	removed.add(protoField{Name: "id", Type: "int64"})
+
+	return protoFile{
+		Path:       fmt.Sprintf("%s/%s.proto", protoDir(moduleName), names.Entity),
+		Imports:    []string{protoDir(moduleName) + "/common.proto", protoWellKnown[protoTimestamp].File},
+		Messages:   []protoMessage{record, get, list, listResponse, create, created, update, remove, removed},
+		Service:    s + "Service",
+		ServiceDoc: fmt.Sprintf("%sService manages %s with the interactor service of the REST API", s, names.EntityPlural),
+		Methods: []protoMethod{
+			{Name: "Get" + s, Input: get.Name, Output: s, Doc: fmt.Sprintf("Get%s returns a %s by id", s, names.Entity)},
+			{Name: "List" + p, Input: list.Name, Output: listResponse.Name, Doc: fmt.Sprintf("List%s returns a page of %s, filtered and sorted like GET /%s", p, names.EntityPlural, names.EntityPlural)},
+			{Name: "Create" + s, Input: create.Name, Output: created.Name, Doc: fmt.Sprintf("Create%s creates a %s and returns its id", s, names.Entity)},
+			{Name: "Update" + s, Input: update.Name, Output: s, Doc: fmt.Sprintf("Update%s replaces a %s and returns it", s, names.Entity)},
+			{Name: "Delete" + s, Input: remove.Name, Output: removed.Name, Doc: fmt.Sprintf("Delete%s deletes a %s", s, names.Entity)},
+		},
+	}
+}
+
+// protoComment writes a doc comment in the given indentation, one line per line of the doc
+func protoComment(doc, indent string) string {
+	if doc == "" {
+		return ""
+	}
+	var b strings.Builder
+	for _, line := range strings.Split(doc, "\n") {
+		b.WriteString(indent + "// " + line + "\n")
+	}
+	return b.String()
+}
+
+// render writes the .proto source of the file
+func (f protoFile) render(moduleName string) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("// Generated by boGO from the schema of %s. Regenerate the service instead of editing this file.\n", moduleName))
+	b.WriteString("syntax = \"proto3\";\n\n")
+	b.WriteString(fmt.Sprintf("package %s;\n\n", protoPackageName(moduleName)))
+	for _, imp := range f.Imports {
+		b.WriteString(fmt.Sprintf("import %q;\n", imp))
+	}
+	if len(f.Imports) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString(fmt.Sprintf("option go_package = %q;\n", protoGoPackage(moduleName)))
+
+	if f.Service != "" {
+		b.WriteString("\n" + protoComment(f.ServiceDoc, ""))
+		b.WriteString(fmt.Sprintf("service %s {\n", f.Service))
+		for _, method := range f.Methods {
+			b.WriteString(protoComment(method.Doc, "  "))
+			b.WriteString(fmt.Sprintf("  rpc %s(%s) returns (%s);\n", method.Name, method.Input, method.Output))
+		}
+		b.WriteString("}\n")
+	}
+
+	for _, message := range f.Messages {
+		b.WriteString("\n" + protoComment(message.Doc, ""))
+		b.WriteString(fmt.Sprintf("message %s {\n", message.Name))
+		if len(message.Reserved) > 0 {
+			reserved := make([]string, len(message.Reserved))
+			for i, number := range message.Reserved {
+				reserved[i] = fmt.Sprint(number)
+			}
+			b.WriteString(fmt.Sprintf("  reserved %s;\n", strings.Join(reserved, ", ")))
+		}
+		for _, field := range message.Fields {
+			label := ""
+			switch {
+			case field.Repeated:
+				label = "repeated "
+			case field.Optional:
+				label = "optional "
+			}
+			b.WriteString(protoComment(field.Doc, "  "))
+			b.WriteString(fmt.Sprintf("  %s%s %s = %d;\n", label, field.Type, field.Name, field.Number))
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+// protoWire appends protobuf wire format, as much of it as file descriptors need
+type protoWire []byte
+
+func (w *protoWire) varint(v uint64) {
+	for v >= 0x80 {
+		*w = append(*w, byte(v)|0x80)
+		v >>= 7
+	}
+	*w = append(*w, byte(v))
+}
+
+func (w *protoWire) uintField(number int, v uint64) {
+	w.varint(uint64(number) << 3)
+	w.varint(v)
+}
+
+func (w *protoWire) bytesField(number int, data []byte) {
+	w.varint(uint64(number)<<3 | 2)
+	w.varint(uint64(len(data)))
+	*w = append(*w, data...)
+}
+
+func (w *protoWire) stringField(number int, s string) {
+	w.bytesField(number, []byte(s))
+}
+
+// qualifiedType returns the fully qualified name of a message type, e.g. .demo_svc.v1.User
+func qualifiedType(pkg, name string) string {
+	if _, ok := protoWellKnown[name]; ok {
+		return "." + name
+	}
+	return "." + pkg + "." + name
+}
+
+// descriptor serializes the FileDescriptorProto of the file, as protoc hands it to protoc-gen-go
+func (f protoFile) descriptor(moduleName string) []byte {
+	pkg := protoPackageName(moduleName)
+
+	var file protoWire
+	file.stringField(1, f.Path)
+	file.stringField(2, pkg)
+	for _, imp := range f.Imports {
+		file.stringField(3, imp)
+	}
+	for _, message := range f.Messages {
+		var msg protoWire
+		msg.stringField(1, message.Name)
+		var oneofs []string
+		for _, field := range message.Fields {
+			var fd protoWire
+			fd.stringField(1, field.Name)
+			fd.uintField(3, uint64(field.Number))
+			if field.Repeated {
+				fd.uintField(4, 3)
+			} else {
+				fd.uintField(4, 1)
+			}
+			if scalar, ok := protoScalars[field.Type]; ok {
+				fd.uintField(5, uint64(scalar.Kind))
+			} else {
+				fd.uintField(5, protoMessageKind)
+				fd.stringField(6, qualifiedType(pkg, field.Type))
+			}
+			if field.Optional {
+				// proto3 optional fields are alone in a synthetic oneof
+				fd.uintField(9, uint64(len(oneofs)))
+				oneofs = append(oneofs, "_"+field.Name)
+			}
+			fd.stringField(10, protoJSONName(field.Name))
+			if field.Optional {
+				fd.uintField(17, 1)
+			}
+			msg.bytesField(2, fd)
+		}
+		for _, oneof := range oneofs {
+			var decl protoWire
+			decl.stringField(1, oneof)
+			msg.bytesField(8, decl)
+		}
+		for _, number := range message.Reserved {
+			// A reserved range holds its start and the number after its end
+			var reserved protoWire
+			reserved.uintField(1, uint64(number))
+			reserved.uintField(2, uint64(number+1))
+			msg.bytesField(9, reserved)
+		}
+		file.bytesField(4, msg)
+	}
+	if f.Service != "" {
+		var svc protoWire
+		svc.stringField(1, f.Service)
+		for _, method := range f.Methods {
+			var md protoWire
+			md.stringField(1, method.Name)
+			md.stringField(2, qualifiedType(pkg, method.Input))
+			md.stringField(3, qualifiedType(pkg, method.Output))
+			svc.bytesField(2, md)
+		}
+		file.bytesField(6, svc)
+	}
+	var options protoWire
+	options.stringField(11, protoGoPackage(moduleName))
+	file.bytesField(8, options)
+	file.stringField(12, "proto3")
+	return file
+}
+
+// protoJSONName returns the JSON name protoc gives a field, e.g. createdAt for created_at
+func protoJSONName(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, c := range name {
+		switch {
+		case c == '_':
+			upper = true
+		case upper:
+			b.WriteString(strings.ToUpper(string(c)))
+			upper = false
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// protoGoName returns the Go name protoc-gen-go gives a field, e.g. CreatedAt for created_at and
+// ApiUrl for api_url. Names taken by the methods of messages get a trailing underscore.
+func protoGoName(name string) string {
+	var b []byte
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c == '_' && i == 0:
+			b = append(b, 'X')
+		case c == '_' && i+1 < len(name) && name[i+1] >= 'a' && name[i+1] <= 'z':
+			// Skipped, the next letter is capitalized
+		case c >= '0' && c <= '9':
+			b = append(b, c)
+		default:
+			if c >= 'a' && c <= 'z' {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+			for ; i+1 < len(name) && name[i+1] >= 'a' && name[i+1] <= 'z'; i++ {
+				b = append(b, name[i+1])
+			}
+		}
+	}
+	goName := string(b)
+	if slices.Contains([]string{"Reset", "String", "ProtoMessage", "ProtoReflect", "Descriptor"}, goName) {
+		goName += "_"
+	}
+	return goName
+}
+
+// goType returns the Go type of a field in its message struct
+func (field protoField) goType() string {
+	var goType string
+	if scalar, ok := protoScalars[field.Type]; ok {
+		goType = scalar.GoType
+		if field.Optional {
+			goType = "*" + goType
+		}
+	} else if known, ok := protoWellKnown[field.Type]; ok {
+		goType = "*" + known.GoType
+	} else {
+		goType = "*" + field.Type
+	}
+	if field.Repeated {
+		goType = "[]" + goType
+	}
+	return goType
+}
+
+// structTag returns the struct tag protoc-gen-go gives a field
+func (field protoField) structTag() string {
+	wire := "bytes"
+	if scalar, ok := protoScalars[field.Type]; ok {
+		wire = scalar.Wire
+	}
+	label := "opt"
+	if field.Repeated {
+		label = "rep"
+	}
+	tag := fmt.Sprintf("%s,%d,%s,name=%s", wire, field.Number, label, field.Name)
+	if json := protoJSONName(field.Name); json != field.Name {
+		tag += ",json=" + json
+	}
+	tag += ",proto3"
+	if field.Optional {
+		tag += ",oneof"
+	}
+	return fmt.Sprintf("`protobuf:%q json:\"%s,omitempty\"`", tag, field.Name)
+}
+
+// getter returns the nil-safe getter protoc-gen-go generates for a field
+func (field protoField) getter(message string) string {
+	goName := protoGoName(field.Name)
+	goType := field.goType()
+	zero := "nil"
+	value := "x." + goName
+	condition := "x != nil"
+	if scalar, ok := protoScalars[field.Type]; ok && !field.Repeated {
+		goType = scalar.GoType
+		zero = scalar.Zero
+		if field.Optional {
+			value = "*x." + goName
+			condition += " && x." + goName + " != nil"
+		}
+	}
+	return fmt.Sprintf("\nfunc (x *%s) Get%s() %s {\n\tif %s {\n\t\treturn %s\n\t}\n\treturn %s\n}\n",
+		message, goName, goType, condition, value, zero)
+}
+
+// protoFileVar returns the prefix protoc-gen-go gives the variables of a file, e.g. file_demo_svc_v1_user_proto
+func protoFileVar(path string) string {
+	return "file_" + regexp.MustCompile(`[^A-Za-z0-9]`).ReplaceAllString(path, "_")
+}
+
+// goBytes formats data as the lines of a Go byte slice literal
+func goBytes(data []byte) string {
+	var b strings.Builder
+	for i, c := range data {
+		if i%16 == 0 {
+			b.WriteString("\t")
+		}
+		b.WriteString(fmt.Sprintf("0x%02x,", c))
+		if i%16 == 15 || i == len(data)-1 {
+			b.WriteString("\n")
+		} else {
+			b.WriteString(" ")
+		}
+	}
+	return b.String()
+}
+
+// generateProtoGo renders the message types of a .proto file the way protoc-gen-go does, with its
+// descriptor serialized by boGO so the service builds without protoc
+func generateProtoGo(moduleName string, file protoFile) string {
+	pkg := protoPackageName(moduleName)
+	fileVar := protoFileVar(file.Path)
+
+	local := map[string]int{}
+	goTypes := make([]string, 0, len(file.Messages))
+	for i, message := range file.Messages {
+		local[message.Name] = i
+		goTypes = append(goTypes, fmt.Sprintf("\t(*%s)(nil), // %d: %s.%s\n", message.Name, i, pkg, message.Name))
+	}
+
+	// Message types of other files follow those of this file in the order they are first used
+	index := func(name string) int {
+		if i, ok := local[name]; ok {
+			return i
+		}
+		goType := name
+		fullName := pkg + "." + name
+		if known, ok := protoWellKnown[name]; ok {
+			goType, fullName = known.GoType, name
+		}
+		local[name] = len(goTypes)
+		goTypes = append(goTypes, fmt.Sprintf("\t(*%s)(nil), // %d: %s\n", goType, len(goTypes), fullName))
+		return local[name]
+	}
+
+	var messages, depIdxs, oneofWrappers strings.Builder
+	imports := map[string]bool{}
+	deps := 0
+	for i, message := range file.Messages {
+		var fields, getters strings.Builder
+		hasOptional := false
+		for _, field := range message.Fields {
+			fields.WriteString(protoComment(field.Doc, "\t"))
+			fields.WriteString(fmt.Sprintf("\t%s %s %s\n", protoGoName(field.Name), field.goType(), field.structTag()))
+			getters.WriteString(field.getter(message.Name))
+			if field.Optional {
+				hasOptional = true
+			}
+			if _, scalar := protoScalars[field.Type]; scalar {
+				continue
+			}
+			if known, ok := protoWellKnown[field.Type]; ok {
+				imports[known.Import] = true
+			}
+			typeName := strings.TrimPrefix(qualifiedType(pkg, field.Type), ".")
+			depIdxs.WriteString(fmt.Sprintf("\t%d, // %d: %s.%s.%s:type_name -> %s\n", index(field.Type), deps, pkg, message.Name, field.Name, typeName))
+			deps++
+		}
+		if hasOptional {
+			oneofWrappers.WriteString(fmt.Sprintf("\n\t%s_msgTypes[%d].OneofWrappers = []any{}", fileVar, i))
+		}
+
+		messages.WriteString(mustProcessTemplate("grpc-pb-message", map[string]string{
+			"message":  message.Name,
+			"doc":      protoComment(message.Doc, ""),
+			"fields":   fields.String(),
+			"getters":  getters.String(),
+			"file_var": fileVar,
+			"index":    fmt.Sprint(i),
+		}))
+	}
+
+	fieldDeps := deps
+	for _, kind := range []string{"input_type", "output_type"} {
+		for _, method := range file.Methods {
+			name := method.Input
+			if kind == "output_type" {
+				name = method.Output
+			}
+			depIdxs.WriteString(fmt.Sprintf("\t%d, // %d: %s.%s.%s:%s -> %s.%s\n", index(name), deps, pkg, file.Service, method.Name, kind, pkg, name))
+			deps++
+		}
+	}
+	methods := len(file.Methods)
+	depIdxs.WriteString(fmt.Sprintf("\t%d, // [%d:%d] is the sub-list for method output_type\n", fieldDeps+methods, fieldDeps+methods, deps))
+	depIdxs.WriteString(fmt.Sprintf("\t%d, // [%d:%d] is the sub-list for method input_type\n", fieldDeps, fieldDeps, fieldDeps+methods))
+	depIdxs.WriteString(fmt.Sprintf("\t%d, // [%d:%d] is the sub-list for extension type_name\n", fieldDeps, fieldDeps, fieldDeps))
+	depIdxs.WriteString(fmt.Sprintf("\t%d, // [%d:%d] is the sub-list for extension extendee\n", fieldDeps, fieldDeps, fieldDeps))
+	depIdxs.WriteString(fmt.Sprintf("\t0, // [0:%d] is the sub-list for field type_name\n", fieldDeps))
+
+	// Files of the same package are initialized first, as their types are used here
+	var dependencyInits string
+	for _, imp := range file.Imports {
+		if !strings.HasPrefix(imp, "google/protobuf/") {
+			dependencyInits += fmt.Sprintf("\n\t%s_init()", protoFileVar(imp))
+		}
+	}
+
+	var importLines string
+	for _, imp := range slices.Sorted(maps.Keys(imports)) {
+		importLines += "\n\t" + imp
+	}
+
+	numServices := 0
+	if file.Service != "" {
+		numServices = 1
+	}
+	return mustProcessTemplate("grpc-pb", map[string]string{
+		"proto_path":       file.Path,
+		"imports":          importLines,
+		"messages":         messages.String(),
+		"file_var":         fileVar,
+		"file_name":        "F" + strings.TrimPrefix(fileVar, "f"),
+		"raw_desc":         goBytes(file.descriptor(moduleName)),
+		"num_messages":     fmt.Sprint(len(file.Messages)),
+		"num_services":     fmt.Sprint(numServices),
+		"go_types":         strings.Join(goTypes, ""),
+		"dep_idxs":         depIdxs.String(),
+		"dependency_inits": dependencyInits,
+		"oneof_wrappers":   oneofWrappers.String(),
+	})
+}
+
+// generateProtoService renders the gRPC client and server stubs of a table's service the way
+// protoc-gen-go-grpc does
+func generateProtoService(moduleName string, table Table) string {
+	names := namesFor(table)
+	return mustProcessTemplate("grpc-pb-service", map[string]string{
+		"struct_name":   names.Struct,
+		"plural_name":   names.Plural,
+		"proto_package": protoPackageName(moduleName),
+		"proto_path":    fmt.Sprintf("%s/%s.proto", protoDir(moduleName), names.Entity),
+		"service_var":   strings.ToLower(names.Struct[:1]) + names.Struct[1:] + "Service",
+	})
+}
+
+// generateGRPCServer renders the server of every table's service, with the access policy of each method
+func generateGRPCServer(moduleName string, tables []Table) string {
+	var policies, registrations strings.Builder
+	serviceParams := make([]string, len(tables))
+	for i, table := range tables {
+		names := namesFor(table)
+		access := accessFor(table)
+		tenant := ""
+		if _, ok := tenantColumnFor(table); ok {
+			tenant = ", tenant: true"
+		}
+		methods := []struct {
+			name  string
+			roles []string
+		}{
+			{"Get" + names.Struct, access.Read},
+			{"List" + names.Plural, access.Read},
+			{"Create" + names.Struct, access.Write},
+			{"Update" + names.Struct, access.Write},
+			{"Delete" + names.Struct, access.Delete},
+		}
+		for _, method := range methods {
+			policies.WriteString(fmt.Sprintf("\tpb.%sService_%s_FullMethodName: {roles: []string{%s}%s},\n", names.Struct, method.name, quotedList(method.roles), tenant))
+		}
+
+		serviceName := names.Entity + "Service"
+		registrations.WriteString(fmt.Sprintf("\tpb.Register%sServiceServer(server, New%sServer(%s))\n", names.Struct, names.Struct, serviceName))
+		serviceParams[i] = fmt.Sprintf("%s interactor.I%sService", serviceName, names.Struct)
+	}
+
+	vars := map[string]string{
+		"module_name":    moduleName,
+		"policies":       policies.String(),
+		"registrations":  registrations.String(),
+		"service_params": strings.Join(serviceParams, ", "),
+		"auth_params":    "",
+		"auth_fields":    "",
+		"auth_init":      "",
+		"api_key_check":  "",
+		"api_key_doc":    "",
+		"tenancy_import": "",
+		"policy_fields":  "",
+		"policy_doc":     "",
+		"tenant_check":   "",
+		"tenant_scope":   "",
+	}
+
+	// API keys in the x-api-key metadata take precedence over bearer tokens, as on the REST API
+	if usesAPIKeys() {
+		vars["auth_params"] = "apiKeys *auth.APIKeyVerifier, "
+		vars["auth_fields"] = "\n\tapiKeys  *auth.APIKeyVerifier"
+		vars["auth_init"] = ", apiKeys: apiKeys"
+		vars["api_key_doc"] = ", or the API key of the x-api-key metadata"
+		vars["api_key_check"] = "\n\tif key := firstValue(md, \"x-api-key\"); key != \"\" {\n\t\treturn g.apiKeys.Verify(ctx, key)\n\t}\n"
+	}
+
+	if usesTenancy() {
+		var source, lookup string
+		switch tenantSource() {
+		case tenantSourceHeader:
+			source = "the " + strings.ToLower(tenantHeader()) + " metadata"
+			lookup = fmt.Sprintf("\ttenant := firstValue(md, %q)", strings.ToLower(tenantHeader()))
+		case tenantSourceSubdomain:
+			source = "the subdomain of the :authority of the call"
+			lookup = "\ttenant := tenancy.Subdomain(firstValue(md, \":authority\"))"
+		default:
+			source = "the tenant claim of the principal"
+			lookup = "\ttenant := principal.Tenant"
+		}
+		vars["tenancy_import"] = fmt.Sprintf("\n\t\"%s/internal/tenancy\"", moduleName)
+		vars["policy_fields"] = "\n\ttenant bool"
+		vars["policy_doc"] = ".\n// Methods of tables with a tenant column are scoped to the tenant of the call."
+		vars["tenant_check"] = "\n\tif rule.tenant {\n\t\tif ctx, err = withTenant(ctx, md, principal); err != nil {\n\t\t\treturn nil, statusError(err)\n\t\t}\n\t}"
+		vars["tenant_scope"] = "\n" + mustProcessTemplate("grpc-tenant", map[string]string{
+			"tenant_source": source,
+			"tenant_lookup": lookup,
+		})
+	}
+	return mustProcessTemplate("grpc-server", vars)
+}
+
+// generateGRPCServerEntity renders the server of a table's service over its interactor service
+func generateGRPCServerEntity(moduleName string, table Table) string {
+	names := namesFor(table)
+
+	var queryColumns strings.Builder
+	for _, col := range queryColumnsFor(table) {
+		queryColumns.WriteString(fmt.Sprintf("\n\t{QueryKey: %q, DBKey: %q, Kind: reflect.%s},", col.Name, col.Name, col.Kind))
+	}
+
+	vars := map[string]string{
+		"module_name":     moduleName,
+		"struct_name":     names.Struct,
+		"plural_name":     names.Plural,
+		"entity_name":     names.Entity,
+		"entity_plural":   names.EntityPlural,
+		"query_columns":   queryColumns.String(),
+		"include_deleted": "",
+		"expect_version":  "",
+	}
+	// Administrators may add soft-deleted records to listings
+	if usesSoftDelete(table) {
+		vars["include_deleted"] = fmt.Sprintf("\n\tif req.GetIncludeDeleted() {\n\t\tif ctx, err = includeDeleted(ctx, []string{%s}); err != nil {\n\t\t\treturn nil, err\n\t\t}\n\t}", quotedList(adminRolesFor(table)))
+	}
+	// Changes to versioned records must name the version they replace
+	if usesVersioning(table) {
+		vars["expect_version"] = "\n\tctx, err := expectVersion(ctx, req.GetVersion())\n\tif err != nil {\n\t\treturn nil, err\n\t}\n"
+	}
+
+	listTemplate := "grpc-server-list"
+	if usesCursorPagination(table) {
+		listTemplate = "grpc-server-list-cursor"
+	}
+	vars["list_method"] = mustProcessTemplate(listTemplate, vars)
+	return mustProcessTemplate("grpc-server-entity", vars)
+}
+
+// protoRequestValue returns the expression reading a column from a request message into a DTO field of the given type
+func protoRequestValue(col Column, requestType string) string {
+	goName := protoGoName(strings.ToLower(col.Name))
+	switch {
+	case strings.HasPrefix(requestType, "*") && col.GoType == "time.Time":
+		return fmt.Sprintf("optionalTimeOf(req.Get%s())", goName)
+	case strings.HasPrefix(requestType, "*"):
+		return "req." + goName
+	case col.GoType == "time.Time":
+		return fmt.Sprintf("timeOf(req.Get%s())", goName)
+	default:
+		return fmt.Sprintf("req.Get%s()", goName)
+	}
+}
+
+// generateGRPCMapperEntity renders the conversions between a table's messages and its DTOs
+func generateGRPCMapperEntity(moduleName string, table Table) string {
+	names := namesFor(table)
+
+	var response, create, update strings.Builder
+	response.WriteString("\n\t\tId: r.ID,")
+	for _, col := range table.Columns {
+		if col.isMeta() {
+			continue
+		}
+		if col.inResponse() {
+			value := "r." + col.FieldName
+			if col.GoType == "time.Time" {
+				value = fmt.Sprintf("timestampOf(%s)", value)
+			}
+			response.WriteString(fmt.Sprintf("\n\t\t%s: %s,", protoGoName(strings.ToLower(col.Name)), value))
+		}
+		if col.inCreateRequest() {
+			create.WriteString(fmt.Sprintf("\n\t\t%s: %s,", col.FieldName, protoRequestValue(col, col.createType())))
+		}
+		if col.inUpdateRequest() {
+			update.WriteString(fmt.Sprintf("\n\t\t%s: %s,", col.FieldName, protoRequestValue(col, col.updateType())))
+		}
+	}
+	response.WriteString("\n\t\tCreatedAt: timestampOf(r.CreatedAt),\n\t\tUpdatedAt: timestampOf(r.UpdatedAt),")
+	if usesVersioning(table) {
+		response.WriteString("\n\t\tVersion: r.Version,")
+	}
+	if usesSoftDelete(table) {
+		response.WriteString("\n\t\tDeletedAt: optionalTimestampOf(r.DeletedAt),")
+	}
+	if usesAuditColumns() {
+		response.WriteString("\n\t\tCreatedBy: r.CreatedBy,\n\t\tUpdatedBy: r.UpdatedBy,")
+	}
+
+	return mustProcessTemplate("grpc-mapper-entity", map[string]string{
+		"module_name":     moduleName,
+		"struct_name":     names.Struct,
+		"plural_name":     names.Plural,
+		"entity_name":     names.Entity,
+		"entity_plural":   names.EntityPlural,
+		"response_fields": response.String(),
+		"create_fields":   create.String(),
+		"update_fields":   update.String(),
+	})
+}
+
+// generateGRPCServerTest renders the tests of the gRPC API over fake services
+func generateGRPCServerTest(moduleName string, tables []Table) string {
+	var entityTests strings.Builder
+	serverArgs := make([]string, 0, len(tables)+1)
+	if usesAPIKeys() {
+		serverArgs = append(serverArgs, "nil")
+	}
+
+	var roles []string
+	for _, table := range tables {
+		names := namesFor(table)
+		entityTests.WriteString(generateGRPCServerTestEntity(table))
+		serverArgs = append(serverArgs, "fakes."+names.Struct)
+
+		access := accessFor(table)
+		for _, group := range [][]string{access.Read, access.Write, access.Delete, adminRolesFor(table), access.OwnerBypass} {
+			roles = append(roles, group...)
+		}
+	}
+	slices.Sort(roles)
+
+	vars := map[string]string{
+		"module_name":         moduleName,
+		"entity_tests":        entityTests.String(),
+		"server_args":         strings.Join(serverArgs, ", "),
+		"roles":               quotedList(slices.Compact(roles)),
+		"authority":           "bufnet",
+		"authority_doc":       "",
+		"tenant_claim_config": "",
+		"tenant_claim":        "",
+		"tenant_metadata":     "",
+		"first_struct":        namesFor(tables[0]).Struct,
+		"first_plural":        namesFor(tables[0]).Plural,
+		"test_imports":        "",
+	}
+	if strings.Contains(vars["entity_tests"], "timestamppb.") {
+		vars["test_imports"] = "\n\t\"google.golang.org/protobuf/types/known/timestamppb\""
+	}
+	if usesTenancy() {
+		switch tenantSource() {
+		case tenantSourceHeader:
+			vars["tenant_metadata"] = fmt.Sprintf(", %q, \"acme\"", strings.ToLower(tenantHeader()))
+		case tenantSourceSubdomain:
+			vars["authority"] = "acme.api.example.com"
+			vars["authority_doc"] = ", naming the tenant by its subdomain"
+		default:
+			vars["tenant_claim_config"] = fmt.Sprintf(", TenantClaim: %q", tenantClaim())
+			vars["tenant_claim"] = fmt.Sprintf("\n\t\t%q: \"acme\",", tenantClaim())
+		}
+	}
+	return mustProcessTemplate("grpc-server-test", vars)
+}
+
+// protoSampleField returns a request message member setting a column of the given request type to a valid value
+func protoSampleField(col Column, requestType string) string {
+	value := sampleLiteral(col)
+	switch {
+	case strings.HasPrefix(requestType, "*"):
+		value = fmt.Sprintf("ptr(%s(%s))", col.GoType, value)
+	case col.GoType == "time.Time":
+		value = fmt.Sprintf("timestamppb.New(%s)", value)
+	}
+	return fmt.Sprintf("%s: %s", protoGoName(strings.ToLower(col.Name)), value)
+}
+
+// generateGRPCServerTestEntity renders the test of the RPCs of a table
+func generateGRPCServerTestEntity(table Table) string {
+	names := namesFor(table)
+
+	// Creates take the NOT NULL members without a default, replacements every NOT NULL member
+	var fields, updateFields []string
+	for _, col := range table.Columns {
+		if col.inCreateRequest() && col.requiresValue() {
+			fields = append(fields, protoSampleField(col, col.requestType()))
+		}
+		if col.inUpdateRequest() && col.requiredOnUpdate() {
+			updateFields = append(updateFields, protoSampleField(col, col.updateType()))
+		}
+	}
+
+	var validationCheck string
+	if len(fields) > 0 {
+		validationCheck = fmt.Sprintf(`	_, err := c.Create%[1]s(ctx, &pb.Create%[1]sRequest{})
+	if st := status.Convert(err); st.Code() != codes.InvalidArgument || len(fieldViolations(st)) == 0 {
+		t.Fatalf("Create%[1]s() without required fields error = %%v, want InvalidArgument listing the fields", err)
+	}
+
+`, names.Struct)
+	}
+
+	vars := map[string]string{
+		"struct_name":      names.Struct,
+		"plural_name":      names.Plural,
+		"entity_name":      names.Entity,
+		"create_fields":    strings.Join(fields, ", "),
+		"update_fields":    "",
+		"validation_check": validationCheck,
+		"version":          "",
+		"version_check":    "",
+	}
+	if len(updateFields) > 0 {
+		vars["update_fields"] = ", " + strings.Join(updateFields, ", ")
+	}
+	if usesVersioning(table) {
+		vars["version"] = ", Version: 1"
+		vars["version_check"] = fmt.Sprintf(`	if _, err := c.Delete%[1]s(ctx, &pb.Delete%[1]sRequest{Id: id}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Delete%[1]s() without a version error = %%v, want FailedPrecondition", err)
+	}
+`, names.Struct)
+	}
+	return mustProcessTemplate("grpc-server-test-entity", vars)
+}
+
+// generateGRPC creates the .proto files of every table in proto/, their Go stubs and the gRPC server
+// in internal/interactor/grpc. They are rewritten on every run, so they follow the schema, but keep
+// the field numbers of the .proto files they replace.
+func generateGRPC(moduleName string, tables []Table) error {
+	protoRoot := filepath.Join(moduleName, "proto")
+	grpcDir := filepath.Join(moduleName, "internal", "interactor", "grpc")
+	pbDir := filepath.Join(grpcDir, "pb")
+	vars := map[string]string{"module_name": moduleName}
+
+	// numbered keeps the field numbers of the .proto file a file replaces
+	numbered := func(file protoFile) (protoFile, error) {
+		previous, err := readProtoNumbers(filepath.Join(protoRoot, file.Path))
+		if err != nil {
+			return file, fmt.Errorf("reading the field numbers of %s: %w", file.Path, err)
+		}
+		file.keepNumbers(previous)
+		return file, nil
+	}
+
+	common, err := numbered(protoCommonFile(moduleName))
+	if err != nil {
+		return err
+	}
+	files := map[string]string{
+		filepath.Join(protoRoot, common.Path):    common.render(moduleName),
+		filepath.Join(pbDir, "common.pb.go"):     generateProtoGo(moduleName, common),
+		filepath.Join(grpcDir, "server.go"):      generateGRPCServer(moduleName, tables),
+		filepath.Join(grpcDir, "errors.go"):      mustProcessTemplate("grpc-errors", vars),
+		filepath.Join(grpcDir, "errors_test.go"): mustProcessTemplate("grpc-errors-test", vars),
+		filepath.Join(grpcDir, "query.go"):       mustProcessTemplate("grpc-query", vars),
+		filepath.Join(grpcDir, "mapper.go"):      mustProcessTemplate("grpc-mapper", vars),
+		filepath.Join(grpcDir, "server_test.go"): generateGRPCServerTest(moduleName, tables),
+	}
+	for _, table := range tables {
+		entity := namesFor(table).Entity
+		file, err := numbered(protoEntityFile(moduleName, table))
+		if err != nil {
+			return err
+		}
+		files[filepath.Join(protoRoot, file.Path)] = file.render(moduleName)
+		files[filepath.Join(pbDir, entity+".pb.go")] = generateProtoGo(moduleName, file)
+		files[filepath.Join(pbDir, entity+"_grpc.pb.go")] = generateProtoService(moduleName, table)
+		files[filepath.Join(grpcDir, entity+"_server.go")] = generateGRPCServerEntity(moduleName, table)
+		files[filepath.Join(grpcDir, entity+"_mapper.go")] = generateGRPCMapperEntity(moduleName, table)
+	}
+
+	for filePath, content := range files {
+		if err := writeFile(filePath, content); err != nil {
+			return err
+		}
+		fmt.Printf("Created gRPC file: %s\n", filePath)
+	}
+	return nil
+}
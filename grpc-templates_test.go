@@ -0,0 +1,88 @@
+package main
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// protoUsers describes the .proto file of a users table with the given columns besides the timestamps
+func protoUsers(t *testing.T, lines ...string) protoFile {
+	t.Helper()
+	var columns []Column
+	for _, line := range append(lines, "created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()", "updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()") {
+		columns = append(columns, parsedColumn(t, line))
+	}
+	table := Table{Name: "users", Columns: columns}
+	assignFieldNames(&table)
+	return protoEntityFile("shop", table)
+}
+
+// protoNumbersOf returns the field numbers of a message of the file
+func protoNumbersOf(t *testing.T, file protoFile, message string) map[string]int {
+	t.Helper()
+	for _, m := range file.Messages {
+		if m.Name == message {
+			numbers := map[string]int{}
+			for _, field := range m.Fields {
+				numbers[field.Name] = field.Number
+			}
+			return numbers
+		}
+	}
+	t.Fatalf("%s has no message %s", file.Path, message)
+	return nil
+}
+
+func TestProtoFieldNumbers(t *testing.T) {
+	withConfig(t, Config{})
+	path := filepath.Join(t.TempDir(), "user.proto")
+	write := func(file protoFile) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(file.render("shop")), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	regenerate := func(file protoFile) protoFile {
+		t.Helper()
+		previous, err := readProtoNumbers(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		file.keepNumbers(previous)
+		write(file)
+		return file
+	}
+
+	// A first run numbers the fields in column order
+	first := regenerate(protoUsers(t, "id BIGSERIAL PRIMARY KEY", "name TEXT NOT NULL", "email TEXT NOT NULL"))
+	want := map[string]int{"id": 1, "name": 2, "email": 3, "created_at": 4, "updated_at": 5, "deleted_at": 6}
+	if got := protoNumbersOf(t, first, "User"); !maps.Equal(got, want) {
+		t.Errorf("first run numbers = %v, want %v", got, want)
+	}
+
+	// Later runs keep the numbers, number new columns after them and reserve dropped ones
+	second := regenerate(protoUsers(t, "id BIGSERIAL PRIMARY KEY", "phone TEXT", "email TEXT NOT NULL"))
+	want = map[string]int{"id": 1, "phone": 7, "email": 3, "created_at": 4, "updated_at": 5, "deleted_at": 6}
+	if got := protoNumbersOf(t, second, "User"); !maps.Equal(got, want) {
+		t.Errorf("second run numbers = %v, want %v", got, want)
+	}
+	if !strings.Contains(second.render("shop"), "message User {\n  reserved 2;\n") {
+		t.Errorf("second run does not reserve the number of name:\n%s", second.render("shop"))
+	}
+
+	// A column added back does not take its reserved number
+	third := regenerate(protoUsers(t, "id BIGSERIAL PRIMARY KEY", "name TEXT NOT NULL", "phone TEXT", "email TEXT NOT NULL"))
+	want = map[string]int{"id": 1, "name": 8, "phone": 7, "email": 3, "created_at": 4, "updated_at": 5, "deleted_at": 6}
+	if got := protoNumbersOf(t, third, "User"); !maps.Equal(got, want) {
+		t.Errorf("third run numbers = %v, want %v", got, want)
+	}
+	for _, m := range third.Messages {
+		if m.Name == "User" && !slices.Equal(m.Reserved, []int{2}) {
+			t.Errorf("third run reserves %v, want [2]", m.Reserved)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// inflectionRule rewrites a word matching pattern using replacement
+type inflectionRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// Inflector converts English words between their singular and plural forms
+type Inflector struct {
+	plurals     []inflectionRule
+	singulars   []inflectionRule
+	irregulars  map[string]string // singular -> plural
+	reverse     map[string]string // plural -> singular
+	uncountable map[string]bool
+}
+
+// inflect is the inflector shared by every generator, configured from the boGO config
+var inflect = newInflector(InflectionConfig{})
+
+// Rules are evaluated last-to-first, so more specific rules appear later in each list
+var defaultPluralRules = [][2]string{
+	{`$`, "s"},
+	{`s$`, "s"},
+	{`^(ax|test)is$`, "${1}es"},
+	{`(alias|status|campus|bus|virus|bonus|census|gas|canvas|atlas|lens)$`, "${1}es"},
+	{`(octop)us$`, "${1}i"},
+	{`(octop)i$`, "${1}i"},
+	{`(buffal|tomat|potat|her|ech)o$`, "${1}oes"},
+	{`([ti])um$`, "${1}a"},
+	{`([ti])a$`, "${1}a"},
+	{`sis$`, "ses"},
+	{`^(kni|wi|li)fe$`, "${1}ves"},
+	{`^(wol|hal|shel|cal|sel|el|thie|loa|lea|scar|dwar|whar)f$`, "${1}ves"},
+	{`(hive)$`, "${1}s"},
+	{`([^aeiouy]|qu)y$`, "${1}ies"},
+	{`(x|ch|ss|sh|z)$`, "${1}es"},
+	{`(matr|vert|ind)(?:ix|ex)$`, "${1}ices"},
+	{`^(m|l)ouse$`, "${1}ice"},
+	{`^(m|l)ice$`, "${1}ice"},
+	{`^(ox)$`, "${1}en"},
+	{`^(oxen)$`, "${1}"},
+	{`(quiz)$`, "${1}zes"},
+}
+
+var defaultSingularRules = [][2]string{
+	{`s$`, ""},
+	{`(ss)$`, "${1}"},
+	{`(n)ews$`, "${1}ews"},
+	{`([ti])a$`, "${1}um"},
+	{`((a)naly|(b)a|(d)iagno|(p)arenthe|(p)rogno|(s)ynop|(t)he)(sis|ses)$`, "${1}sis"},
+	{`(^analy)(sis|ses)$`, "${1}sis"},
+	{`^(kni|wi|li)ves$`, "${1}fe"},
+	{`^(wol|hal|shel|cal|sel|el|thie|loa|lea|scar|dwar|whar)ves$`, "${1}f"},
+	{`([^aeiouy]|qu)ies$`, "${1}y"},
+	{`(s)eries$`, "${1}eries"},
+	{`(m)ovies$`, "${1}ovie"},
+	{`(x|ch|ss|sh|z)es$`, "${1}"},
+	{`^(m|l)ice$`, "${1}ouse"},
+	{`(bus|campus|bonus|census|gas|canvas|atlas|lens)(es)?$`, "${1}"},
+	{`(o)es$`, "${1}"},
+	{`(shoe)s$`, "${1}"},
+	{`(cris|test)(is|es)$`, "${1}is"},
+	{`^(a)x[ie]s$`, "${1}xis"},
+	{`(octop)(us|i)$`, "${1}us"},
+	{`(alias|status|virus)(es)?$`, "${1}"},
+	{`^(ox)en`, "${1}"},
+	{`(vert|ind)ices$`, "${1}ex"},
+	{`(matr)ices$`, "${1}ix"},
+	{`(quiz)zes$`, "${1}"},
+	{`(database)s$`, "${1}"},
+}
+
+var defaultIrregulars = map[string]string{
+	"person": "people",
+	"man":    "men",
+	"woman":  "women",
+	"child":  "children",
+	"sex":    "sexes",
+	"move":   "moves",
+	"zombie": "zombies",
+	"goose":  "geese",
+	"tooth":  "teeth",
+	"foot":   "feet",
+	"cactus": "cacti",
+}
+
+var defaultUncountables = []string{
+	"equipment", "information", "rice", "money", "species", "series",
+	"fish", "sheep", "jeans", "police", "news", "metadata", "feedback",
+	"software", "hardware", "staff", "inventory", "audio", "media",
+}
+
+// newInflector builds an inflector from the default English rules plus user overrides
+func newInflector(overrides InflectionConfig) *Inflector {
+	in := &Inflector{
+		irregulars:  map[string]string{},
+		reverse:     map[string]string{},
+		uncountable: map[string]bool{},
+	}
+
+	for _, rule := range defaultPluralRules {
+		in.plurals = append(in.plurals, inflectionRule{regexp.MustCompile("(?i)" + rule[0]), rule[1]})
+	}
+	for _, rule := range defaultSingularRules {
+		in.singulars = append(in.singulars, inflectionRule{regexp.MustCompile("(?i)" + rule[0]), rule[1]})
+	}
+	for singular, plural := range defaultIrregulars {
+		in.addIrregular(singular, plural)
+	}
+	for _, word := range defaultUncountables {
+		in.uncountable[word] = true
+	}
+
+	// User overrides always win over the built-in rules
+	for singular, plural := range overrides.Irregular {
+		in.addIrregular(singular, plural)
+	}
+	for _, word := range overrides.Uncountable {
+		in.uncountable[strings.ToLower(word)] = true
+	}
+
+	return in
+}
+
+// addIrregular registers a singular/plural pair in both directions
+func (in *Inflector) addIrregular(singular, plural string) {
+	singular = strings.ToLower(singular)
+	plural = strings.ToLower(plural)
+	delete(in.uncountable, singular)
+	delete(in.uncountable, plural)
+	in.irregulars[singular] = plural
+	in.reverse[plural] = singular
+}
+
+// Pluralize returns the plural form of word. For snake_case names only the last segment is inflected.
+func (in *Inflector) Pluralize(word string) string {
+	prefix, last := splitLastSegment(word)
+	return prefix + in.inflect(last, in.irregulars, in.reverse, in.plurals)
+}
+
+// Singularize returns the singular form of word. For snake_case names only the last segment is inflected.
+func (in *Inflector) Singularize(word string) string {
+	prefix, last := splitLastSegment(word)
+	return prefix + in.inflect(last, in.reverse, in.irregulars, in.singulars)
+}
+
+// inflect applies irregular forms, uncountables and finally the ordered rule list to a single word
+func (in *Inflector) inflect(word string, irregulars, inverse map[string]string, rules []inflectionRule) string {
+	lower := strings.ToLower(word)
+	if lower == "" || in.uncountable[lower] {
+		return word
+	}
+	if target, ok := irregulars[lower]; ok {
+		return matchCase(word, target)
+	}
+	// Already in the requested form
+	if _, ok := inverse[lower]; ok {
+		return word
+	}
+
+	for i := len(rules) - 1; i >= 0; i-- {
+		if rules[i].pattern.MatchString(word) {
+			return rules[i].pattern.ReplaceAllString(word, rules[i].replacement)
+		}
+	}
+	return word
+}
+
+// splitLastSegment splits "user_addresses" into "user_" and "addresses"
+func splitLastSegment(word string) (string, string) {
+	idx := strings.LastIndex(word, "_")
+	if idx < 0 {
+		return "", word
+	}
+	return word[:idx+1], word[idx+1:]
+}
+
+// matchCase gives target the leading capitalization of original
+func matchCase(original, target string) string {
+	if original != "" && strings.ToUpper(original[:1]) == original[:1] {
+		return strings.ToUpper(target[:1]) + target[1:]
+	}
+	return target
+}
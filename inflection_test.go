@@ -0,0 +1,99 @@
+package main
+
+import "testing"
+
+func TestSingularize(t *testing.T) {
+	in := newInflector(InflectionConfig{})
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"users", "user"},
+		{"user_addresses", "user_address"},
+		{"categories", "category"},
+		{"people", "person"},
+		{"statuses", "status"},
+		{"cafes", "cafe"},
+		{"safes", "safe"},
+		{"caves", "cave"},
+		{"drives", "drive"},
+		{"archives", "archive"},
+		{"knives", "knife"},
+		{"wolves", "wolf"},
+		{"shelves", "shelf"},
+		{"leaves", "leaf"},
+		{"gas", "gas"},
+		{"gases", "gas"},
+		{"lens", "lens"},
+		{"lenses", "lens"},
+		{"canvas", "canvas"},
+		{"canvases", "canvas"},
+		{"virus", "virus"},
+		{"viruses", "virus"},
+		{"analyses", "analysis"},
+		{"matrices", "matrix"},
+		{"news", "news"},
+		{"sheep", "sheep"},
+		{"Users", "User"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := in.Singularize(tt.word); got != tt.want {
+				t.Errorf("Singularize(%q) = %q, want %q", tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPluralize(t *testing.T) {
+	in := newInflector(InflectionConfig{})
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"user", "users"},
+		{"user_address", "user_addresses"},
+		{"category", "categories"},
+		{"person", "people"},
+		{"status", "statuses"},
+		{"cafe", "cafes"},
+		{"safe", "safes"},
+		{"drive", "drives"},
+		{"knife", "knives"},
+		{"wolf", "wolves"},
+		{"leaf", "leaves"},
+		{"roof", "roofs"},
+		{"gas", "gases"},
+		{"lens", "lenses"},
+		{"canvas", "canvases"},
+		{"virus", "viruses"},
+		{"octopus", "octopi"},
+		{"box", "boxes"},
+		{"quiz", "quizzes"},
+		{"news", "news"},
+		{"User", "Users"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := in.Pluralize(tt.word); got != tt.want {
+				t.Errorf("Pluralize(%q) = %q, want %q", tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInflectionOverrides(t *testing.T) {
+	in := newInflector(InflectionConfig{
+		Irregular:   map[string]string{"cactus": "cactuses"},
+		Uncountable: []string{"Gas"},
+	})
+	if got := in.Pluralize("cactus"); got != "cactuses" {
+		t.Errorf("Pluralize(cactus) = %q, want the configured cactuses", got)
+	}
+	if got := in.Singularize("cactuses"); got != "cactus" {
+		t.Errorf("Singularize(cactuses) = %q, want cactus", got)
+	}
+	if got := in.Pluralize("gas"); got != "gas" {
+		t.Errorf("Pluralize(gas) = %q, want gas as configured uncountable", got)
+	}
+}
@@ -10,17 +10,18 @@ func generateUnifiedApplicationInterfaces(moduleName string, tables []Table) str
 	var interfaces strings.Builder
 
 	for _, table := range tables {
-		structName := toCamelCase(table.Name)
-		if strings.HasSuffix(structName, "s") {
-			structName = structName[:len(structName)-1]
-		}
+		names := namesFor(table)
+		structName := names.Struct
 
-		entityName := strings.ToLower(structName)
+		entityName := names.Entity
 
 		// Use template for interface generation
 		interfaceVars := map[string]string{
-			"entity_name": entityName,
-			"struct_name": structName,
+			"entity_name":         entityName,
+			"entity_param":        names.Var,
+			"plural_param":        names.PluralVar,
+			"struct_name":         structName,
+			"soft_delete_methods": softDeleteRepositoryMethods(table),
 		}
 
 		interfaceResult, err := processTemplate("application-interface-content", interfaceVars)
@@ -48,17 +49,27 @@ func generateUnifiedInteractorInterfaces(moduleName string, tables []Table) stri
 	var interfaces strings.Builder
 
 	for _, table := range tables {
-		structName := toCamelCase(table.Name)
-		if strings.HasSuffix(structName, "s") {
-			structName = structName[:len(structName)-1]
-		}
+		names := namesFor(table)
+		structName := names.Struct
 
-		entityName := strings.ToLower(structName)
+		entityName := names.Entity
 
 		// Use template for interface generation
 		interfaceVars := map[string]string{
-			"entity_name": entityName,
-			"struct_name": structName,
+			"entity_name":         entityName,
+			"entity_param":        names.Var,
+			"plural_param":        names.PluralVar,
+			"struct_name":         structName,
+			"plural_name":         names.Plural,
+			"history_method":      "",
+			"soft_delete_methods": "",
+		}
+		if usesAuditLog() {
+			interfaceVars["history_method"] = "\n\tHistory(ctx context.Context, id int64) (dto.AuditHistory, error)"
+		}
+		if usesSoftDelete(table) {
+			interfaceVars["soft_delete_methods"] = fmt.Sprintf("\n\tRestore(ctx context.Context, id int64) (dto.%sResponse, error)"+
+				"\n\tPurge(ctx context.Context, id int64) error", structName)
 		}
 
 		interfaceResult, err := processTemplate("interactor-interface-content", interfaceVars)
@@ -80,3 +91,11 @@ func generateUnifiedInteractorInterfaces(moduleName string, tables []Table) stri
 	}
 	return content
 }
+
+// softDeleteRepositoryMethods returns the repository port methods of soft-deleted tables
+func softDeleteRepositoryMethods(table Table) string {
+	if !usesSoftDelete(table) {
+		return ""
+	}
+	return "\n\tRestore(ctx context.Context, id int64) error\n\tPurge(ctx context.Context, id int64) error"
+}
@@ -7,18 +7,25 @@ import (
 
 func main() {
 	if len(os.Args) < 3 {
-		fmt.Println("Usage: go run *.go <module-name> <sql-schema-file>")
-		fmt.Println("Example: go run *.go user-service schema.sql")
+		fmt.Println("Usage: go run *.go <module-name> <sql-schema-file> [config-file]")
+		fmt.Println("Example: go run *.go user-service schema.sql bogo.json")
 		os.Exit(1)
 	}
 
 	moduleName := os.Args[1]
 	sqlSchemaFile := os.Args[2]
+	configFile := ""
+	if len(os.Args) > 3 {
+		configFile = os.Args[3]
+	}
 
 	fmt.Printf("Creating hexagonal architecture for module: %s\n", moduleName)
 	fmt.Printf("Using SQL schema from: %s\n", sqlSchemaFile)
+	if configFile != "" {
+		fmt.Printf("Using config from: %s\n", configFile)
+	}
 
-	err := createHexagonalArchitecture(moduleName, sqlSchemaFile)
+	err := createHexagonalArchitecture(moduleName, sqlSchemaFile, configFile)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
@@ -2,40 +2,35 @@ package main
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 )
 
 // generateDomainModel creates domain model struct based on table schema
 func generateDomainModel(table Table) string {
-	structName := toCamelCase(table.Name)
-	if strings.HasSuffix(structName, "s") {
-		structName = structName[:len(structName)-1]
-	}
+	names := namesFor(table)
+	structName := names.Struct
 
 	var fields strings.Builder
 
-	// Add MetaField if table doesn't have explicit ID
-	hasID := false
-	for _, col := range table.Columns {
-		if strings.ToLower(col.Name) == "id" {
-			hasID = true
-			break
-		}
+	// Every entity embeds MetaField, which also covers an explicitly declared id column
+	fields.WriteString("\tMetaField\n")
+	if usesSoftDelete(table) {
+		fields.WriteString("\tSoftDeleteField\n")
 	}
-
-	if !hasID {
-		fields.WriteString("\tMetaField\n")
+	if usesVersioning(table) {
+		fields.WriteString("\tVersionField\n")
+	}
+	if usesAuditColumns() {
+		fields.WriteString("\tAuditField\n")
 	}
 
 	// Add table-specific fields
 	for _, col := range table.Columns {
-		fieldName := toCamelCase(col.Name)
+		fieldName := col.FieldName
 
 		// Skip meta fields if they're explicitly defined
-		if strings.ToLower(col.Name) == "created_at" ||
-			strings.ToLower(col.Name) == "updated_at" ||
-			strings.ToLower(col.Name) == "deleted_at" ||
-			strings.ToLower(col.Name) == "is_deleted" {
+		if col.isMeta() {
 			continue
 		}
 
@@ -46,7 +41,7 @@ func generateDomainModel(table Table) string {
 	variables := map[string]string{
 		"import_statement": getImportStatement(table),
 		"struct_name":      structName,
-		"entity_name":      strings.ToLower(structName),
+		"entity_name":      names.Entity,
 		"fields":           fields.String(),
 		"table_name":       table.Name,
 	}
@@ -79,43 +74,21 @@ func getImportStatement(table Table) string {
 	return ""
 }
 
-// getDTOImportStatement returns import statements for DTO files
-func getDTOImportStatement(table Table) string {
-	hasTimeFields := false
-	for _, col := range table.Columns {
-		// Skip meta fields and id as they're handled differently in DTOs
-		if strings.ToLower(col.Name) == "id" ||
-			strings.ToLower(col.Name) == "created_at" ||
-			strings.ToLower(col.Name) == "updated_at" ||
-			strings.ToLower(col.Name) == "deleted_at" ||
-			strings.ToLower(col.Name) == "is_deleted" {
-			continue
-		}
-		if col.GoType == "time.Time" {
-			hasTimeFields = true
-			break
-		}
-	}
-	if hasTimeFields {
-		return `	"time"`
-	}
-	return ""
-}
-
 // generateApplicationInterface creates repository interface for application layer
 func generateApplicationInterface(moduleName string, table Table) string {
-	structName := toCamelCase(table.Name)
-	if strings.HasSuffix(structName, "s") {
-		structName = structName[:len(structName)-1]
-	}
+	names := namesFor(table)
+	structName := names.Struct
 
 	variables := map[string]string{
 		"module_name":  moduleName,
-		"entity_name":  strings.ToLower(structName),
+		"entity_name":  names.Entity,
 		"struct_name":  structName,
-		"entity_param": strings.ToLower(structName),
+		"entity_param": names.Var,
+		"plural_param": names.PluralVar,
 	}
 
+	variables["soft_delete_methods"] = softDeleteRepositoryMethods(table)
+
 	result, err := processTemplate("application-interface", variables)
 	if err != nil {
 		panic(fmt.Sprintf("Failed to process application-interface template: %v", err))
@@ -123,85 +96,312 @@ func generateApplicationInterface(moduleName string, table Table) string {
 	return result
 }
 
-// generateDTO creates DTO structs and methods based on table schema - FIXED VERSION
+// generateDTO creates the create, update and response DTOs of a table and their mappings
 func generateDTO(moduleName string, table Table) string {
-	structName := toCamelCase(table.Name)
-	if strings.HasSuffix(structName, "s") {
-		structName = structName[:len(structName)-1]
+	names := namesFor(table)
+	structName := names.Struct
+
+	var createFields, updateFields, responseFields strings.Builder
+	var createMarshalFields, createDefaults, updateMarshalFields, unmarshalFields strings.Builder
+	var patchCases, patchRules strings.Builder
+	auditResponseFields, auditUnmarshalFields := "", ""
+	versionResponseField, versionUnmarshalField := "", ""
+	if usesVersioning(table) {
+		versionResponseField = "\tVersion int64 `json:\"version\"`\n"
+		versionUnmarshalField = "\n\td.Version = domainModel.Version"
+	}
+	deletedResponseField, deletedUnmarshalField := "", ""
+	if usesSoftDelete(table) {
+		deletedResponseField = "\tDeletedAt *time.Time `json:\"deleted_at\"`\n"
+		deletedUnmarshalField = "\n\tif domainModel.DeletedAt.Valid {\n\t\td.DeletedAt = &domainModel.DeletedAt.Time\n\t}"
+	}
+	if usesAuditColumns() {
+		auditResponseFields = "\tCreatedBy string `json:\"created_by\"`\n\tUpdatedBy string `json:\"updated_by\"`\n"
+		auditUnmarshalFields = "\n\td.CreatedBy = domainModel.CreatedBy\n\td.UpdatedBy = domainModel.UpdatedBy"
 	}
 
-	// Generate DTO fields based on domain model fields
-	var fields strings.Builder
-	fields.WriteString("\tID int64 `json:\"id,omitempty\"`\n")
+	// Add table-specific fields according to their column semantics
+	for _, col := range table.Columns {
+		if col.isMeta() {
+			continue
+		}
 
-	// Generate field mappings for Marshal/Unmarshal methods
-	var marshalFields strings.Builder
-	var unmarshalFields strings.Builder
+		fieldName := col.FieldName
+		member := strings.ToLower(col.Name)
 
-	// Add table-specific fields
-	for _, col := range table.Columns {
-		// Skip meta fields as they're handled differently in DTOs
-		if strings.ToLower(col.Name) == "id" ||
-			strings.ToLower(col.Name) == "created_at" ||
-			strings.ToLower(col.Name) == "updated_at" ||
-			strings.ToLower(col.Name) == "deleted_at" ||
-			strings.ToLower(col.Name) == "is_deleted" {
+		if col.inCreateRequest() {
+			createFields.WriteString(requestField(col, col.createType(), col.requestRules()))
+			createMarshalFields.WriteString(fmt.Sprintf("\n\t\t%s: %s,", fieldName, requestValue(col, col.createType())))
+			// Only a missing member keeps the database default, an explicit zero value is stored
+			if col.DefaultValue != "" {
+				createDefaults.WriteString(fmt.Sprintf("\n\tif d.%s == nil {\n\t\tdomainModel.Defaults = append(domainModel.Defaults, %q)\n\t}", fieldName, member))
+			}
+		}
+		// Responses always carry every member, zero values included
+		if col.inResponse() {
+			responseFields.WriteString(fmt.Sprintf("\t%s %s `json:\"%s\"`\n", fieldName, col.GoType, member))
+			unmarshalFields.WriteString(fmt.Sprintf("\n\td.%s = domainModel.%s", fieldName, fieldName))
+		}
+		if !col.inUpdateRequest() {
 			continue
 		}
+		updateFields.WriteString(requestField(col, col.updateType(), col.updateRules()))
+		updateMarshalFields.WriteString(fmt.Sprintf("\n\t\t%s: %s,", fieldName, requestValue(col, col.updateType())))
+
+		// Add merge patch decoding, keyed by JSON member and stored by column
+		onNull := fmt.Sprintf(`patch["%s"] = nil
+				continue`, col.Name)
+		if !col.IsNullable {
+			onNull = `return nil, errs.Invalid("field %q cannot be null", member)`
+		}
+		patchCases.WriteString(fmt.Sprintf(`
+		case "%s":
+			if isNull(raw) {
+				%s
+			}
+			var value %s
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return nil, errs.Invalid("field %%q: %%v", member, err)
+			}
+			patch["%s"] = value`, member, onNull, col.GoType, col.Name))
+
+		if rules := col.validationRules(); len(rules) > 0 {
+			patchRules.WriteString(fmt.Sprintf("\n\t\t%q: %q,", col.Name, strings.Join(rules, ",")))
+		}
+	}
+
+	variables := map[string]string{
+		"module_name":            moduleName,
+		"dto_struct_name":        structName,
+		"entity_name":            names.Entity,
+		"plural_name":            names.Plural,
+		"struct_name":            structName,
+		"create_fields":          createFields.String(),
+		"update_fields":          updateFields.String(),
+		"response_fields":        responseFields.String(),
+		"create_marshal_fields":  createMarshalFields.String(),
+		"create_defaults":        createDefaults.String(),
+		"update_marshal_fields":  updateMarshalFields.String(),
+		"unmarshal_fields":       unmarshalFields.String(),
+		"patch_cases":            patchCases.String(),
+		"patch_rules":            patchRules.String(),
+		"read_only_members":      strings.Join(readOnlyMembers(table), ", "),
+		"audit_response_fields":  auditResponseFields,
+		"audit_unmarshal_fields": auditUnmarshalFields,
+		"deleted_response":       deletedResponseField,
+		"version_response":       versionResponseField,
+		"version_unmarshal":      versionUnmarshalField,
+		"deleted_unmarshal":      deletedUnmarshalField,
+		"upsert_key":             upsertKeyDoc(table),
+		"upsert_fields":          "",
+		"upsert_marshal":         "",
+	}
+
+	// Upserts match rows by ID unless the table names another key, and replace versioned rows at the named version
+	if upsertKeyFor(table)[0].IsPrimaryKey {
+		variables["upsert_fields"] += "\tID int64 `json:\"id,omitempty\" validate:\"omitempty,gt=0\"`\n"
+		variables["upsert_marshal"] += "\n\tdomainModel.ID = d.ID"
+	}
+	if usesVersioning(table) {
+		variables["upsert_fields"] += "\tVersion int64 `json:\"version,omitempty\" validate:\"omitempty,gt=0\"`\n"
+		variables["upsert_marshal"] += "\n\tdomainModel.Version = d.Version"
+	}
+
+	result, err := processTemplate("dto", variables)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to process dto template: %v", err))
+	}
+	return result
+}
+
+// requestField returns the request DTO field of a column, carrying the given validator rules
+func requestField(col Column, goType string, rules []string) string {
+	member := strings.ToLower(col.Name)
+	if len(rules) > 0 {
+		return fmt.Sprintf("\t%s %s `json:\"%s,omitempty\" validate:\"%s\"`\n", col.FieldName, goType, member, strings.Join(rules, ","))
+	}
+	return fmt.Sprintf("\t%s %s `json:\"%s,omitempty\"`\n", col.FieldName, goType, member)
+}
+
+// requestValue returns the expression reading a request DTO field of the given type into the domain model
+func requestValue(col Column, goType string) string {
+	if goType != col.GoType {
+		return fmt.Sprintf("deref(d.%s)", col.FieldName)
+	}
+	return "d." + col.FieldName
+}
+
+// readOnlyMembers returns the quoted JSON members of a table that patch documents may not set
+func readOnlyMembers(table Table) []string {
+	members := []string{`"id"`, `"created_at"`, `"updated_at"`}
+	if usesVersioning(table) {
+		members = append(members, `"version"`)
+	}
+	if usesSoftDelete(table) {
+		members = append(members, `"deleted_at"`)
+	}
+	if usesAuditColumns() {
+		members = append(members, `"created_by"`, `"updated_by"`)
+	}
+	for _, col := range table.Columns {
+		if !col.isMeta() && !col.inUpdateRequest() {
+			members = append(members, fmt.Sprintf("%q", strings.ToLower(col.Name)))
+		}
+	}
+	return members
+}
 
-		fieldName := toCamelCase(col.Name)
-		jsonTag := fmt.Sprintf("`json:\"%s,omitempty\"`", strings.ToLower(col.Name))
+// generateDTOTest renders the tests of the patch decoding of a table
+func generateDTOTest(moduleName string, table Table) string {
+	var cases strings.Builder
+	usesTime := false
+	for _, col := range table.Columns {
+		if col.isMeta() || !col.inUpdateRequest() {
+			continue
+		}
+		value := sampleLiteral(col)
+		if col.GoType == "int64" || col.GoType == "float64" {
+			value = fmt.Sprintf("%s(%s)", col.GoType, value)
+		}
+		usesTime = usesTime || col.GoType == "time.Time"
+		cases.WriteString(fmt.Sprintf("\n\t\t{%q, %q, %s, %t},", strings.ToLower(col.Name), col.Name, value, col.IsNullable))
+	}
 
-		fields.WriteString(fmt.Sprintf("\t%s %s %s\n", fieldName, col.GoType, jsonTag))
+	vars := map[string]string{
+		"module_name":       moduleName,
+		"struct_name":       namesFor(table).Struct,
+		"patch_cases":       cases.String(),
+		"read_only_members": strings.Join(readOnlyMembers(table), ", "),
+		"test_imports":      "",
+	}
+	if usesTime {
+		vars["test_imports"] = "\n\t\"time\""
+	}
+	return mustProcessTemplate("dto-test-entity", vars)
+}
 
-		// Add field mappings
-		marshalFields.WriteString(fmt.Sprintf("\n\t\t%s: d.%s,", fieldName, fieldName))
-		unmarshalFields.WriteString(fmt.Sprintf("\n\td.%s = domainModel.%s", fieldName, fieldName))
+// upsertKeyDoc names the upsert key columns of a table for doc comments
+func upsertKeyDoc(table Table) string {
+	var names []string
+	for _, col := range upsertKeyFor(table) {
+		names = append(names, col.Name)
 	}
+	return strings.Join(names, ", ")
+}
 
-	// Use singular table name for DTO struct and plural for collections (like Users []User)
-	dtoStructName := structName
-	pluralName := structName + "s"
+// generateDTOPatch creates the patch document parsing shared by all DTOs
+func generateDTOPatch(moduleName string) string {
+	result, err := processTemplate("dto-patch", map[string]string{"module_name": moduleName})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to process dto-patch template: %v", err))
+	}
+	return result
+}
 
-	// Check if DTO needs time import
-	dtoImportStatement := getDTOImportStatement(table)
+// generateDTOValidation creates the request validation helpers shared by all DTOs
+func generateDTOValidation(moduleName string) string {
+	result, err := processTemplate("dto-validation", map[string]string{"module_name": moduleName})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to process dto-validation template: %v", err))
+	}
+	return result
+}
 
+// generateApplicationBatch creates the chunked, optionally atomic application of batches shared by all services
+func generateApplicationBatch(moduleName string) string {
 	variables := map[string]string{
-		"module_name":      moduleName,
-		"dto_struct_name":  dtoStructName,
-		"entity_name":      strings.ToLower(dtoStructName),
-		"fields":           fields.String(),
-		"plural_name":      pluralName,
-		"struct_name":      structName,
-		"import_statement": dtoImportStatement,
-		"marshal_fields":   marshalFields.String(),
-		"unmarshal_fields": unmarshalFields.String(),
+		"module_name": moduleName,
+		"chunk_size":  fmt.Sprint(batchChunkSize()),
 	}
-
-	result, err := processTemplate("dto", variables)
+	result, err := processTemplate("application-batch", variables)
 	if err != nil {
-		panic(fmt.Sprintf("Failed to process dto template: %v", err))
+		panic(fmt.Sprintf("Failed to process application-batch template: %v", err))
 	}
 	return result
 }
 
+// generateApplicationBatchTest creates the tests of the shared batch application
+func generateApplicationBatchTest(moduleName string) string {
+	return mustProcessTemplate("application-batch-test", map[string]string{"module_name": moduleName})
+}
+
 // generateApplicationService creates concrete application service implementation
 func generateApplicationService(moduleName string, table Table) string {
-	structName := toCamelCase(table.Name)
-	if strings.HasSuffix(structName, "s") {
-		structName = structName[:len(structName)-1]
-	}
+	names := namesFor(table)
+	structName := names.Struct
 
 	serviceName := fmt.Sprintf("%sDomain", structName)
-	repoFieldName := fmt.Sprintf("%sRepo", strings.ToLower(structName))
+	repoFieldName := fmt.Sprintf("%sRepo", names.Entity)
 
 	variables := map[string]string{
 		"module_name":     moduleName,
 		"service_name":    serviceName,
-		"entity_name":     strings.ToLower(structName),
+		"entity_name":     names.Entity,
 		"struct_name":     structName,
+		"table_name":      table.Name,
 		"repo_field_name": repoFieldName,
+		"service_imports": fmt.Sprintf("\n\t\"%s/internal/domain/model\"", moduleName),
+		"service_fields":  "\n\ttransactor UnitOfWork",
+		"service_params":  ", transactor UnitOfWork",
+		"service_init":    "\n\t\ttransactor: transactor,",
+		"create_record":   "",
+		"upsert_record":   "",
+		"delete_check":    "",
+		"delete_record":   "",
+		"deleted_var":     "_",
+	}
+
+	// Audited services change records and write their audit log entries in one transaction
+	mutationTemplate := "application-service-mutations"
+	softDeleteTemplate := "application-soft-delete"
+	if usesAuditLog() {
+		mutationTemplate = "application-service-audited"
+		softDeleteTemplate = "application-soft-delete-audit"
+		variables["service_fields"] = "\n\ttransactor UnitOfWork\n\tauditLog   iAuditLog"
+		variables["service_params"] = ", transactor UnitOfWork, auditLog iAuditLog"
+		variables["service_init"] = "\n\t\ttransactor: transactor,\n\t\tauditLog:   auditLog,"
+		variables["create_record"] = `
+		for _, item := range items {
+			if err := s.record(ctx, model.AuditCreate, item.ID, nil, item); err != nil {
+				return err
+			}
+		}`
+		variables["upsert_record"] = `
+		for k, item := range items {
+			action := model.AuditUpdate
+			if previous[k] == nil {
+				action = model.AuditCreate
+			}
+			if err := s.record(ctx, action, item.ID, previous[k], item); err != nil {
+				return err
+			}
+		}`
+		variables["delete_record"] = `
+		for k := range deleted {
+			if err := s.record(ctx, model.AuditDelete, deleted[k].ID, &deleted[k], nil); err != nil {
+				return err
+			}
+		}`
+		variables["deleted_var"] = "deleted"
+	}
+
+	// Versioned records are only deleted at the version the caller names
+	if usesVersioning(table) {
+		variables["delete_check"] = fmt.Sprintf(`
+		current := make(map[int64]int64, len(deleted))
+		for _, row := range deleted {
+			current[row.ID] = row.Version
+		}
+		if err := checkBatchVersions(%q, entities, batch, current); err != nil {
+			return err
+		}`, names.Entity)
+		variables["deleted_var"] = "deleted"
+	}
+	variables["mutation_methods"] = mustProcessTemplate(mutationTemplate, variables)
+	if usesSoftDelete(table) {
+		variables["mutation_methods"] += mustProcessTemplate(softDeleteTemplate, variables)
 	}
+	variables["mutation_methods"] += mustProcessTemplate("application-batch-methods", variables)
 
 	result, err := processTemplate("application-service", variables)
 	if err != nil {
@@ -212,22 +412,20 @@ func generateApplicationService(moduleName string, table Table) string {
 
 // generateInteractorService creates service interface for interactor layer
 func generateInteractorService(moduleName string, table Table) string {
-	structName := toCamelCase(table.Name)
-	if strings.HasSuffix(structName, "s") {
-		structName = structName[:len(structName)-1]
-	}
+	names := namesFor(table)
+	structName := names.Struct
 
 	serviceName := fmt.Sprintf("I%sService", structName)
 	dtoName := structName
-	dtoPlural := structName + "s"
+	dtoPlural := names.Plural
 
 	variables := map[string]string{
 		"module_name":      moduleName,
 		"service_name":     serviceName,
-		"entity_name":      strings.ToLower(structName),
-		"dto_plural_param": strings.ToLower(dtoPlural),
+		"entity_name":      names.Entity,
+		"dto_plural_param": names.PluralVar,
 		"dto_plural":       dtoPlural,
-		"dto_param":        strings.ToLower(dtoName),
+		"dto_param":        names.Var,
 		"dto_name":         dtoName,
 	}
 
@@ -240,16 +438,14 @@ func generateInteractorService(moduleName string, table Table) string {
 
 // generateInteractorAdapter creates adapter implementation that connects interactor to application layer
 func generateInteractorAdapter(moduleName string, table Table) string {
-	structName := toCamelCase(table.Name)
-	if strings.HasSuffix(structName, "s") {
-		structName = structName[:len(structName)-1]
-	}
+	names := namesFor(table)
+	structName := names.Struct
 
 	serviceName := fmt.Sprintf("I%sService", structName)
 	adapterName := fmt.Sprintf("%sAdapter", structName)
 	appServiceName := fmt.Sprintf("%sDomain", structName)
 	dtoName := structName
-	dtoPlural := structName + "s"
+	dtoPlural := names.Plural
 
 	variables := map[string]string{
 		"module_name":      moduleName,
@@ -257,11 +453,19 @@ func generateInteractorAdapter(moduleName string, table Table) string {
 		"service_name":     serviceName,
 		"app_service_name": strings.ToLower(appServiceName),
 		"app_service_type": appServiceName,
-		"entity_name":      strings.ToLower(structName),
-		"dto_plural_param": strings.ToLower(dtoPlural),
+		"entity_name":      names.Entity,
+		"dto_plural_param": names.PluralVar,
 		"dto_plural":       dtoPlural,
-		"dto_param":        strings.ToLower(dtoName),
+		"dto_param":        names.Var,
 		"dto_name":         dtoName,
+		"history_method":   "",
+	}
+	if usesAuditLog() {
+		variables["history_method"] = mustProcessTemplate("interactor-adapter-history", variables)
+	}
+	variables["soft_delete_methods"] = ""
+	if usesSoftDelete(table) {
+		variables["soft_delete_methods"] = mustProcessTemplate("interactor-adapter-restore", variables)
 	}
 
 	result, err := processTemplate("interactor-adapter", variables)
@@ -271,27 +475,349 @@ func generateInteractorAdapter(moduleName string, table Table) string {
 	return result
 }
 
+// generatePostgresRepositoryTest renders the test binding the queries of a repository to the request context
+func generatePostgresRepositoryTest(moduleName string, table Table) string {
+	vars := map[string]string{
+		"repo_name":        namesFor(table).Struct + "Repo",
+		"base_context":     "context.Background()",
+		"test_imports":     "",
+		"expect_begin":     "",
+		"expect_commit":    "",
+		"finish_tests":     "",
+		"std_test_imports": "",
+	}
+	// Tenant-scoped connections open a transaction that sets the tenant for row-level security
+	if _, ok := tenantColumnFor(table); ok {
+		vars["base_context"] = `tenancy.WithTenant(context.Background(), "acme")`
+		vars["test_imports"] = fmt.Sprintf("\n\n\t\"%s/internal/tenancy\"\n\n\t\"github.com/DATA-DOG/go-sqlmock\"", moduleName)
+		vars["expect_begin"] = `
+			mock.ExpectBegin()
+			mock.ExpectExec("set_config").WithArgs("acme").WillReturnResult(sqlmock.NewResult(0, 0))`
+		vars["expect_commit"] = "\n\t\t\tmock.ExpectCommit()"
+		// A failed commit is reported, and a failed call rolled back
+		vars["std_test_imports"] = "\n\t\"errors\""
+		vars["finish_tests"] = mustProcessTemplate("postgres-conn-tenant-test", vars)
+	}
+	return mustProcessTemplate("postgres-conn-test", vars)
+}
+
 // generatePostgresRepository creates PostgreSQL repository implementation
 func generatePostgresRepository(moduleName string, table Table) string {
-	structName := toCamelCase(table.Name)
-	if strings.HasSuffix(structName, "s") {
-		structName = structName[:len(structName)-1]
-	}
+	names := namesFor(table)
+	structName := names.Struct
 
 	repoName := fmt.Sprintf("%sRepo", structName)
 
+	// Columns that filters and sorts may reference
+	var columnAllowlist strings.Builder
+	columnAllowlist.WriteString("\n\t\"id\": true,")
+	for _, col := range table.Columns {
+		// Write-only columns must not be observable through filters
+		if col.isMeta() || col.IsWriteOnly {
+			continue
+		}
+		columnAllowlist.WriteString(fmt.Sprintf("\n\t\"%s\": true,", col.Name))
+	}
+	columnAllowlist.WriteString("\n\t\"created_at\": true,")
+	columnAllowlist.WriteString("\n\t\"updated_at\": true,")
+	if usesSoftDelete(table) {
+		columnAllowlist.WriteString("\n\t\"deleted_at\": true,")
+	}
+	if usesAuditColumns() {
+		columnAllowlist.WriteString("\n\t\"created_by\": true,")
+		columnAllowlist.WriteString("\n\t\"updated_by\": true,")
+	}
+
+	// Inserts read back the ID and the columns a create may leave to their database default
+	returned := []string{`{Name: "id"}`}
+	for _, col := range table.Columns {
+		if col.inCreateRequest() && col.DefaultValue != "" {
+			returned = append(returned, fmt.Sprintf("{Name: %q}", strings.ToLower(col.Name)))
+		}
+	}
+
+	// Keyset pagination orders by the configured cursor column (validated against the schema)
+	cursor, _ := cursorColumnFor(table)
+
 	variables := map[string]string{
 		"module_name":        moduleName,
 		"repo_name":          repoName,
-		"entity_name":        strings.ToLower(structName),
-		"entity_name_plural": strings.ToLower(structName) + "s",
+		"entity_name":        names.Entity,
+		"entity_name_plural": names.PluralVar,
 		"struct_name":        structName,
-		"entity_param":       strings.ToLower(structName),
+		"entity_param":       names.Var,
+		"column_allowlist":   columnAllowlist.String(),
+		"returned_columns":   strings.Join(returned, ", "),
+		"cursor_column":      cursor.Name,
+		"cursor_field":       cursor.FieldName,
+		"cursor_type":        cursor.GoType,
 	}
 
+	addRepositoryAccess(moduleName, table, variables)
+	addRepositoryBatch(table, variables)
+
 	result, err := processTemplate("postgres-repository", variables)
 	if err != nil {
 		panic(fmt.Sprintf("Failed to process postgres-repository template: %v", err))
 	}
 	return result
 }
+
+// addRepositoryAccess fills in the connection and row-level access methods of a repository: tenant
+// scoping when tenancy is enabled, ownership when the table has an owner column, and no rules otherwise.
+// With audit columns, the principal behind every change is stamped on the row.
+func addRepositoryAccess(moduleName string, table Table, variables map[string]string) {
+	var stdImports, imports []string
+	var restrictRules, claimRules, patchRules, helpers strings.Builder
+	param := variables["entity_param"]
+	connTemplate := "postgres-conn"
+
+	if tenant, ok := tenantColumnFor(table); ok {
+		connTemplate = "postgres-conn-tenant"
+		imports = append(imports, fmt.Sprintf("%s/internal/tenancy", moduleName))
+		variables["table_name"] = table.Name
+		variables["tenant_column"] = tenant.Name
+		variables["tenant_type"] = tenant.GoType
+		variables["tenant_parse"] = "\ttenantID = id"
+		if tenant.GoType == "int64" {
+			stdImports = append(stdImports, "strconv")
+			variables["tenant_parse"] = "\ttenantID, err = strconv.ParseInt(id, 10, 64)\n" +
+				"\tif err != nil {\n\t\treturn tenantID, errs.Forbidden(\"invalid tenant \" + strconv.Quote(id))\n\t}"
+		}
+
+		restrictRules.WriteString(fmt.Sprintf(`
+	tenantID, err := repo.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query = query.Where("%s = ?", tenantID)
+`, tenant.Name))
+		claimRules.WriteString(fmt.Sprintf(`
+	tenantID, err := repo.tenant(ctx)
+	if err != nil {
+		return err
+	}
+	%s.%s = tenantID
+`, param, tenant.FieldName))
+		patchRules.WriteString(fmt.Sprintf(`
+	if _, ok := fields["%s"]; ok {
+		return errs.Forbidden("%s cannot be changed")
+	}
+`, tenant.Name, tenant.Name))
+		helpers.WriteString(mustProcessTemplate("postgres-access-tenant", variables))
+	}
+
+	if owner, ok := ownerColumnFor(table); ok {
+		imports = append(imports, fmt.Sprintf("%s/internal/auth", moduleName))
+		variables["owner_column"] = owner.Name
+		variables["owner_type"] = owner.GoType
+		variables["owner_bypass"] = quotedList(accessFor(table).OwnerBypass)
+		variables["owner_parse"] = "\towner = principal.Subject"
+		if owner.GoType == "int64" {
+			stdImports = append(stdImports, "strconv")
+			variables["owner_parse"] = fmt.Sprintf("\towner, err = strconv.ParseInt(principal.Subject, 10, 64)\n"+
+				"\tif err != nil {\n\t\treturn owner, false, errs.Forbidden(\"principal cannot own %s\")\n\t}", variables["entity_name_plural"])
+		}
+
+		restrictRules.WriteString(fmt.Sprintf(`
+	owner, restricted, err := repo.owner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if restricted {
+		query = query.Where("%s = ?", owner)
+	}
+`, owner.Name))
+		claimRules.WriteString(fmt.Sprintf(`
+	owner, restricted, err := repo.owner(ctx)
+	if err != nil {
+		return err
+	}
+	if restricted {
+		%s.%s = owner
+	}
+`, param, owner.FieldName))
+		patchRules.WriteString(fmt.Sprintf(`
+	if _, ok := fields["%s"]; ok {
+		_, restricted, err := repo.owner(ctx)
+		if err != nil {
+			return err
+		}
+		if restricted {
+			return errs.Forbidden("%s can only be changed by an administrator")
+		}
+	}
+`, owner.Name, owner.Name))
+		helpers.WriteString(mustProcessTemplate("postgres-access-owner", variables))
+	}
+
+	// Soft deletes set deleted_at through GORM, which also leaves deleted rows out of every query
+	softDelete := usesSoftDelete(table)
+	omitted := []string{"id", "created_at"}
+	variables["delete_doc"] = "permanently deletes"
+	variables["delete_update"] = fmt.Sprintf("Delete(&model.%s{})", variables["struct_name"])
+	variables["restore_fields"] = `"deleted_at": nil`
+	if softDelete {
+		omitted = append(omitted, "deleted_at")
+		variables["delete_doc"] = "soft deletes"
+	}
+
+	// Audit columns record the principal behind every change
+	var patchStamps []string
+	if usesAuditColumns() {
+		imports = append(imports, fmt.Sprintf("%s/internal/auth", moduleName))
+		claimRules.WriteString(fmt.Sprintf(`
+	actor := auth.Actor(ctx)
+	%s.CreatedBy = actor
+	%s.UpdatedBy = actor
+`, param, param))
+		omitted = append(omitted, "deleted_by")
+		patchStamps = append(patchStamps, `fields["updated_by"] = auth.Actor(ctx)`)
+		variables["restore_fields"] = `"deleted_at": nil, "deleted_by": "", "updated_by": auth.Actor(ctx)`
+		if softDelete {
+			variables["delete_update"] = `Updates(map[string]any{"deleted_at": time.Now(), "deleted_by": auth.Actor(ctx)})`
+		}
+	}
+
+	// Versioned rows only change at the version the caller expects, and every change moves them to the next one
+	entity := variables["entity_name"]
+	variables["update_version"] = ""
+	variables["update_condition"] = ""
+	variables["version_condition"] = ""
+	variables["version_methods"] = ""
+	variables["update_missing"] = fmt.Sprintf("errs.NotFound(%q, %s.ID)", entity, param)
+	variables["id_missing"] = fmt.Sprintf("errs.NotFound(%q, id)", entity)
+	if usesVersioning(table) {
+		variables["update_version"] = fmt.Sprintf(`
+
+	// Only a %s at the expected version is replaced, by the next version
+	expected, err := repo.version(ctx, %s.ID)
+	if err != nil {
+		return err
+	}
+	%s.Version = expected + 1`, entity, param, param)
+		variables["update_condition"] = "\n\t\tWhere(\"version = ?\", expected)."
+		variables["version_condition"] = `
+	if expected, ok := model.ExpectedVersion(ctx); ok {
+		query = query.Where("version = ?", expected)
+	}`
+		variables["update_missing"] = fmt.Sprintf("repo.stale(ctx, %s.ID)", param)
+		variables["id_missing"] = "repo.stale(ctx, id)"
+		variables["version_methods"] = mustProcessTemplate("postgres-version", variables)
+		patchStamps = append(patchStamps, `fields["version"] = gorm.Expr("version + 1")`)
+		variables["restore_fields"] += `, "version": gorm.Expr("version + 1")`
+	}
+
+	// Stamps are added to a copy, leaving the caller's fields untouched
+	variables["patch_stamp"] = ""
+	if len(patchStamps) > 0 {
+		stdImports = append(stdImports, "maps")
+		variables["patch_stamp"] = "\n\n\tfields = maps.Clone(fields)\n\t" + strings.Join(patchStamps, "\n\t")
+	}
+	variables["update_omit"] = quotedList(omitted)
+	variables["soft_delete_methods"] = ""
+	if softDelete {
+		variables["soft_delete_methods"] = mustProcessTemplate("postgres-soft-delete", variables)
+	}
+
+	slices.Sort(stdImports)
+	slices.Sort(imports)
+	variables["access_std_imports"] = ""
+	for _, path := range slices.Compact(stdImports) {
+		variables["access_std_imports"] += fmt.Sprintf("\n\t%q", path)
+	}
+	variables["access_imports"] = ""
+	for _, path := range slices.Compact(imports) {
+		variables["access_imports"] += fmt.Sprintf("\n\t%q", path)
+	}
+	variables["restrict_rules"] = restrictRules.String()
+	variables["claim_rules"] = claimRules.String()
+	variables["patch_rules"] = patchRules.String()
+	variables["access_helpers"] = helpers.String()
+	variables["conn_method"] = mustProcessTemplate(connTemplate, variables)
+	variables["access_methods"] = mustProcessTemplate("postgres-access", variables)
+}
+
+// addRepositoryBatch fills in the batch methods of a repository: bulk inserts sized to the table's
+// columns, upserts on the configured key and bulk deletes. It uses the access variables.
+func addRepositoryBatch(table Table, variables map[string]string) {
+	param := variables["entity_param"]
+	entity := variables["entity_name"]
+	key := upsertKeyFor(table)
+
+	// PostgreSQL binds at most 65535 parameters per statement
+	columns := 3
+	for _, col := range table.Columns {
+		if !col.isMeta() && !col.IsPrimaryKey && !col.IsGenerated {
+			columns++
+		}
+	}
+	if usesSoftDelete(table) {
+		columns++
+	}
+	if usesVersioning(table) {
+		columns++
+	}
+	if usesAuditColumns() {
+		columns += len(auditColumns)
+	}
+	variables["batch_size"] = fmt.Sprint(min(1000, 65535/columns))
+
+	var keyValues, keyNames, conflict []string
+	for _, col := range key {
+		keyValues = append(keyValues, fmt.Sprintf("%s.%s", param, col.FieldName))
+		keyNames = append(keyNames, col.Name)
+		conflict = append(conflict, fmt.Sprintf("{Name: %q}", col.Name))
+	}
+	variables["upsert_key"] = upsertKeyDoc(table)
+	variables["upsert_key_type"] = fmt.Sprintf("[%d]any", len(key))
+	variables["upsert_key_values"] = strings.Join(keyValues, ", ")
+	variables["upsert_key_expr"] = "(" + strings.Join(keyNames, ", ") + ")"
+	variables["upsert_conflict"] = strings.Join(conflict, ", ")
+
+	// Replacements rewrite what an update sets, and stamp the change
+	var updated []string
+	for _, col := range table.Columns {
+		if col.inUpdateRequest() && !slices.Contains(keyNames, col.Name) {
+			updated = append(updated, col.Name)
+		}
+	}
+	updated = append(updated, "updated_at")
+
+	variables["upsert_missing"] = ""
+	variables["upsert_version"] = ""
+	variables["upsert_keep"] = ""
+	if key[0].IsPrimaryKey {
+		variables["upsert_missing"] += fmt.Sprintf(`
+			if %s.ID != 0 {
+				return nil, errs.NotFound(%q, %s.ID)
+			}`, param, entity, param)
+	}
+	if usesVersioning(table) {
+		updated = append(updated, "version")
+		variables["upsert_missing"] += fmt.Sprintf(`
+			if %s.Version != 0 {
+				return nil, errs.Invalid("version is only allowed when replacing an existing %s")
+			}`, param, entity)
+		variables["upsert_version"] = fmt.Sprintf(`
+		if %s.Version != row.Version {
+			return nil, errs.Stale(%q, row.ID)
+		}
+		%s.Version = row.Version + 1`, param, entity, param)
+	}
+	if usesAuditColumns() {
+		updated = append(updated, "updated_by")
+		variables["upsert_keep"] = fmt.Sprintf("\n\t\t%s.CreatedBy = row.CreatedBy", param)
+	}
+	variables["upsert_columns"] = quotedList(updated)
+	variables["batch_methods"] = strings.TrimSuffix(mustProcessTemplate("postgres-batch", variables), "\n")
+}
+
+// quotedList renders values as the elements of a Go string slice literal
+func quotedList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestGenerateDTO(t *testing.T) {
+	withConfig(t, Config{Audit: AuditConfig{Columns: true}})
+
+	var columns []Column
+	for _, line := range []string{
+		"id BIGSERIAL PRIMARY KEY",
+		"quantity INTEGER NOT NULL",
+		"status VARCHAR(20) NOT NULL DEFAULT 'pending'",
+		"placed_at TIMESTAMP DEFAULT NOW()",
+		"tags JSONB DEFAULT '[]'",
+		"external_ref TEXT,  -- @readonly",
+	} {
+		columns = append(columns, parsedColumn(t, line))
+	}
+	table := Table{Name: "orders", Columns: columns}
+	assignFieldNames(&table)
+	dto := generateDTO("shop", table)
+
+	for _, want := range []string{
+		// Required numbers are pointers, and so are defaulted columns on create only
+		"Quantity *int64 `json:\"quantity,omitempty\" validate:\"required\"`",
+		"Status *string `json:\"status,omitempty\" validate:\"omitempty,max=20\"`",
+		// Updates replace every NOT NULL column, defaulted ones included
+		"Status string `json:\"status,omitempty\" validate:\"required,max=20\"`",
+		"PlacedAt *time.Time `json:\"placed_at,omitempty\"`",
+		"Tags []string `json:\"tags,omitempty\"`",
+		"Status: deref(d.Status),",
+		"PlacedAt: deref(d.PlacedAt),",
+		// Only missing defaulted members are left to the database
+		`if d.Status == nil { domainModel.Defaults = append(domainModel.Defaults, "status") }`,
+		`if d.Tags == nil { domainModel.Defaults = append(domainModel.Defaults, "tags") }`,
+		// Read-only columns are only set on create
+		"ExternalRef string `json:\"external_ref,omitempty\"`",
+		`case "id", "created_at", "updated_at", "deleted_at", "created_by", "updated_by", "external_ref":`,
+		// Responses carry every member
+		"Status string `json:\"status\"`",
+		"ExternalRef string `json:\"external_ref\"`",
+		"DeletedAt *time.Time `json:\"deleted_at\"`",
+		"CreatedBy string `json:\"created_by\"`",
+	} {
+		if !strings.Contains(normalizeSpace(dto), normalizeSpace(want)) {
+			t.Errorf("DTO lacks %s", want)
+		}
+	}
+	if strings.Count(dto, "ExternalRef string `json:\"external_ref,omitempty\"`") != 1 {
+		t.Errorf("read-only column is not in the create request alone:\n%s", dto)
+	}
+}
+
+func TestGenerateDomainModelKeepsEmbeddedFieldsUnique(t *testing.T) {
+	withConfig(t, Config{
+		Audit:  AuditConfig{Columns: true},
+		Tables: map[string]TableConfig{"widgets": {Versioned: true}},
+	})
+
+	table := Table{Name: "widgets", Columns: []Column{
+		parsedColumn(t, "id BIGSERIAL PRIMARY KEY"),
+		parsedColumn(t, "soft_delete_field TEXT"),
+		parsedColumn(t, "audit_field TEXT"),
+		parsedColumn(t, "version_field BIGINT"),
+	}}
+	assignFieldNames(&table)
+	source := generateDomainModel(table)
+
+	file, err := parser.ParseFile(token.NewFileSet(), "widgets.go", source, 0)
+	if err != nil {
+		t.Fatalf("generated model does not parse: %v\n%s", err, source)
+	}
+	seen := map[string]bool{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		field, ok := n.(*ast.Field)
+		if !ok {
+			return true
+		}
+		names := field.Names
+		if ident, ok := field.Type.(*ast.Ident); ok && len(names) == 0 {
+			names = []*ast.Ident{ident}
+		}
+		for _, name := range names {
+			if seen[name.Name] {
+				t.Errorf("field %s is declared twice:\n%s", name.Name, source)
+			}
+			seen[name.Name] = true
+		}
+		return true
+	})
+	for _, embedded := range []string{"SoftDeleteField", "VersionField", "AuditField"} {
+		if !seen[embedded] {
+			t.Errorf("model does not embed %s:\n%s", embedded, source)
+		}
+	}
+}
+
+// normalizeSpace collapses the alignment gofmt adds between struct fields
+func normalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
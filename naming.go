@@ -0,0 +1,248 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// commonInitialisms is the golint list of initialisms that stay fully upper-case in Go identifiers
+var commonInitialisms = []string{
+	"ACL", "API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP",
+	"HTTPS", "ID", "IP", "JSON", "LHS", "QPS", "RAM", "RHS", "RPC", "SLA",
+	"SMTP", "SQL", "SSH", "TCP", "TLS", "TTL", "UDP", "UI", "UID", "UUID",
+	"URI", "URL", "UTF8", "VM", "XML", "XMPP", "XSRF", "XSS",
+}
+
+// initialisms is the active initialism set: the golint list plus configured extras
+var initialisms = newInitialismSet(nil)
+
+// newInitialismSet builds the initialism lookup from the golint list and extra entries
+func newInitialismSet(extra []string) map[string]bool {
+	set := map[string]bool{}
+	for _, word := range commonInitialisms {
+		set[word] = true
+	}
+	for _, word := range extra {
+		set[strings.ToUpper(word)] = true
+	}
+	return set
+}
+
+// reservedLowerIdents are names a lowercase generated identifier must never take: Go keywords,
+// predeclared identifiers, packages imported by the generated code and template-local variables
+var reservedLowerIdents = map[string]bool{
+	// Keywords
+	"break": true, "case": true, "chan": true, "const": true, "continue": true,
+	"default": true, "defer": true, "else": true, "fallthrough": true, "for": true,
+	"func": true, "go": true, "goto": true, "if": true, "import": true,
+	"interface": true, "map": true, "package": true, "range": true, "return": true,
+	"select": true, "struct": true, "switch": true, "type": true, "var": true,
+
+	// Predeclared identifiers
+	"any": true, "append": true, "bool": true, "byte": true, "cap": true,
+	"clear": true, "close": true, "comparable": true, "complex": true, "complex64": true,
+	"complex128": true, "copy": true, "delete": true, "error": true, "false": true,
+	"float32": true, "float64": true, "imag": true, "int": true, "int8": true,
+	"int16": true, "int32": true, "int64": true, "iota": true, "len": true,
+	"make": true, "max": true, "min": true, "new": true, "nil": true,
+	"panic": true, "print": true, "println": true, "real": true, "recover": true,
+	"rune": true, "string": true, "true": true, "uint": true, "uint8": true,
+	"uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+
+	// Packages imported by generated code
+	"application": true, "auth": true, "clause": true, "config": true, "context": true, "dto": true,
+	"errors": true, "fmt": true, "gorm": true, "http": true, "httprouter": true,
+	"interactor": true, "json": true, "log": true, "model": true, "postgres": true,
+	"reflect": true, "responsewrapper": true, "rest": true, "strconv": true, "strings": true,
+	"tenancy": true, "time": true, "validator": true,
+	"atomic": true, "base64": true, "bufio": true, "bytes": true, "client": true, "codes": true,
+	"csv": true, "errdetails": true, "errs": true, "graphql": true, "grpc": true, "httptest": true,
+	"io": true, "jwt": true, "maps": true, "math": true, "metadata": true, "mime": true,
+	"net": true, "pb": true, "response": true, "slices": true, "sqlmock": true, "status": true,
+	"sync": true, "testing": true, "testsupport": true, "timestamppb": true, "url": true,
+
+	// Variables declared inside the templates
+	"a": true, "counter": true, "ctx": true, "d": true, "domainModel": true, "entity": true,
+	"err": true, "filter": true, "filters": true, "id": true, "idStr": true,
+	"limit": true, "offset": true, "repo": true, "result": true, "sort": true,
+	"h": true, "ps": true, "r": true, "s": true, "sortings": true,
+	"total": true, "w": true, "wrapper": true,
+	"created": true, "current": true, "deleted": true, "found": true, "group": true,
+	"i": true, "key": true, "keys": true, "previous": true, "query": true,
+	"replaced": true, "row": true, "rows": true,
+	"body": true, "fields": true, "members": true, "patch": true,
+	"actor": true, "after": true, "cancel": true, "finish": true, "column": true, "cursor": true, "db": true,
+	"items": true, "last": true, "nextCursor": true, "ok": true, "owner": true, "restricted": true,
+	"timeout": true, "unbounded": true, "updated": true,
+}
+
+// reservedFieldNames are exported identifiers already used on generated models and DTOs
+var reservedFieldNames = map[string]bool{
+	"MetaField": true, "SoftDeleteField": true, "AuditField": true, "VersionField": true,
+	"TableName": true, "Marshal": true, "Unmarshal": true,
+	"ID": true, "CreatedAt": true, "UpdatedAt": true, "DeletedAt": true, "Defaults": true,
+}
+
+// reservedTypeNames are exported identifiers generated next to the entity types
+var reservedTypeNames = map[string]bool{
+	"MetaField": true, "SoftDeleteField": true, "VersionField": true,
+	"AuditField": true, "AuditEntry": true, "API": true, "APIKey": true,
+
+	// Audit actions
+	"AuditCreate": true, "AuditUpdate": true, "AuditDelete": true, "AuditRestore": true, "AuditPurge": true,
+
+	// Query parsing of package model
+	"Filter": true, "Sort": true, "SortOrder": true, "QueryInfo": true, "FilterOperators": true,
+	"FilterKey": true, "SplitFilterKey": true, "ParseFilters": true, "ParseSortKey": true, "ParseSorting": true,
+	"PageLimit": true, "DefaultPageSize": true, "MaxPageSize": true, "ErrInvalidCursor": true,
+	"OpEq": true, "OpNe": true, "OpGt": true, "OpGte": true, "OpLt": true,
+	"OpLte": true, "OpIn": true, "OpLike": true, "OpBetween": true, "OpIsNull": true,
+
+	// Request context values of package model
+	"WithDeleted": true, "IncludesDeleted": true, "WithExpectedVersion": true, "ExpectedVersion": true,
+}
+
+// metaColumns are the columns provided by MetaField and SoftDeleteField rather than by table-specific fields
+var metaColumns = map[string]bool{
+	"id": true, "created_at": true, "updated_at": true, "deleted_at": true,
+}
+
+// auditColumns are the columns provided by AuditField when audit columns are enabled
+var auditColumns = map[string]bool{
+	"created_by": true, "updated_by": true, "deleted_by": true,
+}
+
+// entityNames holds every name derived from a table, so all generators agree on them
+type entityNames struct {
+	Struct       string // singular Go type name, e.g. UserAddress
+	Plural       string // plural Go type name, e.g. UserAddresses
+	Entity       string // lowercase singular, e.g. useraddress
+	EntityPlural string // lowercase plural used in routes, e.g. useraddresses
+	Var          string // singular variable name, safe to declare in generated code
+	PluralVar    string // plural variable name, safe to declare in generated code
+}
+
+// namesFor derives the singular and plural entity names for a table
+func namesFor(table Table) entityNames {
+	override := generatorConfig.Tables[table.Name]
+
+	singular := override.Singular
+	if singular == "" {
+		singular = inflect.Singularize(strings.ToLower(table.Name))
+	}
+	plural := override.Plural
+	if plural == "" {
+		// A plural table name already is the plural; inflecting it again can only corrupt it
+		plural = strings.ToLower(table.Name)
+		if override.Singular != "" || plural == singular {
+			plural = inflect.Pluralize(singular)
+		}
+	}
+
+	names := entityNames{
+		Struct: toCamelCase(singular),
+		Plural: toCamelCase(plural),
+	}
+	if reservedTypeNames[names.Struct] {
+		names.Struct += "Entity"
+	}
+	names.Entity = strings.ToLower(names.Struct)
+	names.EntityPlural = strings.ToLower(toCamelCase(plural))
+
+	// Uncountable nouns need a distinct collection type name
+	if names.Plural == names.Struct {
+		names.Plural += "List"
+	}
+
+	names.Var = safeIdent(names.Entity, "Entity")
+	names.PluralVar = safeIdent(strings.ToLower(names.Plural), "List")
+	if names.PluralVar == names.Var {
+		names.PluralVar += "List"
+	}
+	return names
+}
+
+// safeIdent appends suffix to a lowercase identifier that would collide with a reserved name
+func safeIdent(name, suffix string) string {
+	if reservedLowerIdents[name] {
+		return name + suffix
+	}
+	return name
+}
+
+// assignFieldNames sets the Go field name of every column, escaping names that collide
+// with generated identifiers or with another column of the same table
+func assignFieldNames(table *Table) {
+	used := map[string]bool{}
+	for i := range table.Columns {
+		col := &table.Columns[i]
+		name := toCamelCase(col.Name)
+		if !metaColumns[strings.ToLower(col.Name)] && reservedFieldNames[name] {
+			name += "Field"
+		}
+		base := name
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s%d", base, n)
+		}
+		used[name] = true
+		col.FieldName = name
+	}
+}
+
+// reservedRoutes are the top-level paths the REST API serves besides its tables
+var reservedRoutes = map[string]bool{
+	"health": true,
+}
+
+// validateEntityNames reports tables whose derived Go type names collide with each other, and
+// tables whose routes or GraphQL types would shadow the reserved ones
+func validateEntityNames(tables []Table) error {
+	owners := map[string]string{}
+	var conflicts []string
+	for _, table := range tables {
+		names := namesFor(table)
+		if reservedRoutes[names.EntityPlural] || (usesGraphQL() && names.EntityPlural == graphQLRoute) {
+			return fmt.Errorf("table %s: route /%s is reserved; set tables.%s.plural in the config to rename it",
+				table.Name, names.EntityPlural, table.Name)
+		}
+		if usesGraphQL() && graphQLTypeNames[names.Struct] {
+			return fmt.Errorf("table %s: type %s is reserved by the GraphQL schema; set tables.%s.singular in the config to rename it",
+				table.Name, names.Struct, table.Name)
+		}
+		for _, ident := range []string{names.Struct, names.Plural} {
+			if owner, exists := owners[ident]; exists && owner != table.Name {
+				conflicts = append(conflicts, fmt.Sprintf("%s (tables %s and %s)", ident, owner, table.Name))
+				continue
+			}
+			owners[ident] = table.Name
+		}
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return fmt.Errorf("conflicting entity names: %s; set tables.<name>.singular/plural in the config to disambiguate",
+			strings.Join(conflicts, ", "))
+	}
+	return nil
+}
+
+// toCamelCase converts snake_case to CamelCase, keeping known initialisms upper-case
+func toCamelCase(s string) string {
+	parts := strings.Split(strings.ToLower(s), "_")
+	for i := range parts {
+		if len(parts[i]) == 0 {
+			continue
+		}
+		upper := strings.ToUpper(parts[i])
+		switch {
+		case initialisms[upper]:
+			parts[i] = upper
+		case strings.HasSuffix(upper, "S") && initialisms[upper[:len(upper)-1]]:
+			// A pluralized initialism keeps its initialism upper-case, e.g. ids -> IDs
+			parts[i] = upper[:len(upper)-1] + "s"
+		default:
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
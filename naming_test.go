@@ -0,0 +1,155 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// withConfig makes cfg the active configuration for the duration of a test
+func withConfig(t *testing.T, cfg Config) {
+	t.Helper()
+	previous := generatorConfig
+	applyConfig(cfg)
+	t.Cleanup(func() { applyConfig(previous) })
+}
+
+func TestNamesFor(t *testing.T) {
+	withConfig(t, Config{Tables: map[string]TableConfig{
+		"user_status": {Singular: "user_status", Plural: "user_statuses"},
+		"member":      {Singular: "account"},
+	}})
+
+	tests := []struct {
+		table string
+		want  entityNames
+	}{
+		{"users", entityNames{Struct: "User", Plural: "Users", Entity: "user", EntityPlural: "users", Var: "user", PluralVar: "users"}},
+		{"user_addresses", entityNames{Struct: "UserAddress", Plural: "UserAddresses", Entity: "useraddress", EntityPlural: "useraddresses", Var: "useraddress", PluralVar: "useraddresses"}},
+		{"person", entityNames{Struct: "Person", Plural: "People", Entity: "person", EntityPlural: "people", Var: "person", PluralVar: "people"}},
+		{"cafes", entityNames{Struct: "Cafe", Plural: "Cafes", Entity: "cafe", EntityPlural: "cafes", Var: "cafe", PluralVar: "cafes"}},
+		{"safes", entityNames{Struct: "Safe", Plural: "Safes", Entity: "safe", EntityPlural: "safes", Var: "safe", PluralVar: "safes"}},
+		{"drives", entityNames{Struct: "Drive", Plural: "Drives", Entity: "drive", EntityPlural: "drives", Var: "drive", PluralVar: "drives"}},
+		{"gas", entityNames{Struct: "Gas", Plural: "Gases", Entity: "gas", EntityPlural: "gases", Var: "gas", PluralVar: "gases"}},
+		{"equipment", entityNames{Struct: "Equipment", Plural: "EquipmentList", Entity: "equipment", EntityPlural: "equipment", Var: "equipment", PluralVar: "equipmentlist"}},
+		{"types", entityNames{Struct: "Type", Plural: "Types", Entity: "type", EntityPlural: "types", Var: "typeEntity", PluralVar: "types"}},
+		{"filters", entityNames{Struct: "FilterEntity", Plural: "Filters", Entity: "filterentity", EntityPlural: "filters", Var: "filterentity", PluralVar: "filtersList"}},
+		{"sorts", entityNames{Struct: "SortEntity", Plural: "Sorts", Entity: "sortentity", EntityPlural: "sorts", Var: "sortentity", PluralVar: "sorts"}},
+		{"member", entityNames{Struct: "Account", Plural: "Accounts", Entity: "account", EntityPlural: "accounts", Var: "account", PluralVar: "accounts"}},
+		{"user_status", entityNames{Struct: "UserStatus", Plural: "UserStatuses", Entity: "userstatus", EntityPlural: "userstatuses", Var: "userstatus", PluralVar: "userstatuses"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.table, func(t *testing.T) {
+			if got := namesFor(Table{Name: tt.table}); got != tt.want {
+				t.Errorf("namesFor(%s) = %+v, want %+v", tt.table, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToCamelCase(t *testing.T) {
+	withConfig(t, Config{Initialisms: []string{"sku"}})
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"name", "Name"},
+		{"user_id", "UserID"},
+		{"api_url", "APIURL"},
+		{"user_uuid", "UserUUID"},
+		{"ids", "IDs"},
+		{"user_ids", "UserIDs"},
+		{"api_urls", "APIURLs"},
+		{"https", "HTTPS"},
+		{"sku", "SKU"},
+		{"skus", "SKUs"},
+		{"status", "Status"},
+		{"Created_At", "CreatedAt"},
+		{"double__underscore", "DoubleUnderscore"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toCamelCase(tt.name); got != tt.want {
+				t.Errorf("toCamelCase(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSafeIdent(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"user", "user"},
+		{"type", "typeEntity"},
+		{"errors", "errorsEntity"},
+		{"ctx", "ctxEntity"},
+		{"errs", "errsEntity"},
+		{"status", "statusEntity"},
+	}
+	for _, tt := range tests {
+		if got := safeIdent(tt.name, "Entity"); got != tt.want {
+			t.Errorf("safeIdent(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAssignFieldNames(t *testing.T) {
+	withConfig(t, Config{})
+
+	table := Table{Name: "items", Columns: []Column{
+		{Name: "id"},
+		{Name: "created_at"},
+		{Name: "table_name"},
+		{Name: "meta_field"},
+		{Name: "soft_delete_field"},
+		{Name: "audit_field"},
+		{Name: "version_field"},
+		{Name: "api_url"},
+		{Name: "API_URL"},
+		{Name: "apiurl"},
+	}}
+	assignFieldNames(&table)
+
+	want := []string{"ID", "CreatedAt", "TableNameField", "MetaFieldField", "SoftDeleteFieldField", "AuditFieldField", "VersionFieldField", "APIURL", "APIURL2", "Apiurl"}
+	for i, col := range table.Columns {
+		if col.FieldName != want[i] {
+			t.Errorf("field name of %s = %q, want %q", col.Name, col.FieldName, want[i])
+		}
+	}
+}
+
+func TestValidateEntityNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		tables  []string
+		wantErr string
+	}{
+		{"distinct", Config{}, []string{"users", "posts"}, ""},
+		{"same entity", Config{}, []string{"user", "users"}, "conflicting entity names: User (tables user and users)"},
+		{"renamed", Config{Tables: map[string]TableConfig{"user": {Singular: "account"}}}, []string{"user", "users"}, ""},
+		{"reserved route", Config{Tables: map[string]TableConfig{"checks": {Plural: "health"}}}, []string{"checks"}, "table checks: route /health is reserved"},
+		{"graphql route", Config{GraphQL: &GraphQLConfig{}, Tables: map[string]TableConfig{"queries": {Plural: "graphql"}}}, []string{"queries"}, "table queries: route /graphql is reserved"},
+		{"graphql type", Config{GraphQL: &GraphQLConfig{}}, []string{"query"}, "table query: type Query is reserved by the GraphQL schema"},
+		{"graphql off", Config{}, []string{"query"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withConfig(t, tt.cfg)
+			var tables []Table
+			for _, name := range tt.tables {
+				tables = append(tables, Table{Name: name})
+			}
+
+			err := validateEntityNames(tables)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("validateEntityNames() = %v, want no error", err)
+			case tt.wantErr != "" && (err == nil || !strings.HasPrefix(err.Error(), tt.wantErr)):
+				t.Fatalf("validateEntityNames() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
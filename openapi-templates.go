@@ -0,0 +1,930 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// oaObject is a JSON object that keeps its members in insertion order, so the spec reads top-down
+type oaObject struct {
+	keys   []string
+	values map[string]any
+}
+
+// obj builds an oaObject from alternating keys and values
+func obj(pairs ...any) *oaObject {
+	o := &oaObject{values: map[string]any{}}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		o.set(pairs[i].(string), pairs[i+1])
+	}
+	return o
+}
+
+// set adds a member, or replaces it in place
+func (o *oaObject) set(key string, value any) *oaObject {
+	if _, exists := o.values[key]; !exists {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+	return o
+}
+
+// setBefore adds a member ahead of another one, or replaces it in place
+func (o *oaObject) setBefore(key string, value any, before string) *oaObject {
+	if _, exists := o.values[key]; !exists {
+		if i := slices.Index(o.keys, before); i >= 0 {
+			o.keys = slices.Insert(o.keys, i, key)
+			o.values[key] = value
+			return o
+		}
+	}
+	return o.set(key, value)
+}
+
+// MarshalJSON encodes the members in insertion order
+func (o *oaObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := marshalUnescaped(key)
+		value, err := marshalUnescaped(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// marshalUnescaped encodes value as JSON without escaping HTML characters
+func marshalUnescaped(value any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// list builds a JSON array
+func list(items ...any) []any {
+	if items == nil {
+		return []any{}
+	}
+	return items
+}
+
+// ref points at a reusable component, e.g. ref("schemas", "User")
+func ref(kind, name string) *oaObject {
+	return obj("$ref", "#/components/"+kind+"/"+name)
+}
+
+// generateOpenAPIJSON renders the OpenAPI document served by the REST API
+func generateOpenAPIJSON(moduleName string, tables []Table) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(generateOpenAPI(moduleName, tables)); err != nil {
+		panic(fmt.Sprintf("Error encoding OpenAPI document: %v", err))
+	}
+	return buf.String()
+}
+
+// generateOpenAPIYAML renders the OpenAPI document as YAML
+func generateOpenAPIYAML(moduleName string, tables []Table) string {
+	var b strings.Builder
+	writeYAMLBlock(&b, generateOpenAPI(moduleName, tables), 0)
+	return b.String()
+}
+
+// yamlPlain matches strings that may be written as plain YAML scalars, unless they hold ": " or " #"
+var yamlPlain = regexp.MustCompile(`^[A-Za-z/][A-Za-z0-9 _./+(),;=*\[\]-]*$`)
+
+// yamlKeywords are plain scalars that YAML parsers read as booleans or null
+var yamlKeywords = map[string]bool{
+	"y": true, "n": true, "yes": true, "no": true, "on": true, "off": true, "true": true, "false": true, "null": true,
+}
+
+// writeYAMLBlock writes the members of an object, or the items of an array, as block YAML at the indent
+func writeYAMLBlock(b *strings.Builder, value any, indent int) {
+	pad := strings.Repeat(" ", indent)
+	switch v := value.(type) {
+	case *oaObject:
+		for _, key := range v.keys {
+			b.WriteString(pad + yamlScalar(key) + ":")
+			writeYAMLValue(b, v.values[key], indent+2)
+		}
+	case []any:
+		for _, item := range v {
+			if o, ok := item.(*oaObject); ok && len(o.keys) > 0 {
+				// The first member shares the line of the item marker
+				var nested strings.Builder
+				writeYAMLBlock(&nested, o, indent+2)
+				b.WriteString(pad + "- " + strings.TrimPrefix(nested.String(), pad+"  "))
+				continue
+			}
+			if nested, ok := item.([]any); ok && len(nested) > 0 {
+				// Nested arrays are written in flow style, which is JSON
+				encoded, _ := marshalUnescaped(nested)
+				b.WriteString(pad + "- " + string(encoded) + "\n")
+				continue
+			}
+			b.WriteString(pad + "-")
+			writeYAMLValue(b, item, indent+2)
+		}
+	}
+}
+
+// writeYAMLValue writes the value of a member or an array item that follows its key or marker
+func writeYAMLValue(b *strings.Builder, value any, indent int) {
+	switch v := value.(type) {
+	case *oaObject:
+		if len(v.keys) == 0 {
+			b.WriteString(" {}\n")
+			return
+		}
+		b.WriteString("\n")
+		writeYAMLBlock(b, v, indent)
+	case []any:
+		if len(v) == 0 {
+			b.WriteString(" []\n")
+			return
+		}
+		b.WriteString("\n")
+		writeYAMLBlock(b, v, indent-2)
+	case string:
+		b.WriteString(" " + yamlScalar(v) + "\n")
+	default:
+		encoded, err := marshalUnescaped(v)
+		if err != nil {
+			panic(fmt.Sprintf("Error encoding OpenAPI value %v: %v", v, err))
+		}
+		b.WriteString(" " + string(encoded) + "\n")
+	}
+}
+
+// yamlScalar writes a string plain when that is unambiguous and as a JSON string, which YAML reads
+// as a double-quoted scalar, otherwise
+func yamlScalar(s string) string {
+	plain := yamlPlain.MatchString(s) && !strings.HasSuffix(s, " ") && !strings.Contains(s, " #")
+	if plain && !yamlKeywords[strings.ToLower(s)] {
+		return s
+	}
+	encoded, _ := marshalUnescaped(s)
+	return string(encoded)
+}
+
+// generateOpenAPI builds the OpenAPI 3.1 document of every route registered by rest-routes.template
+func generateOpenAPI(moduleName string, tables []Table) *oaObject {
+	schemas := openAPISharedSchemas()
+	parameters := openAPISharedParameters(tables)
+	paths := obj("/health", obj("get", obj(
+		"tags", list("health"),
+		"operationId", "getHealth",
+		"summary", "Check the health of the service",
+		"security", list(),
+		"responses", obj("200", okResponse("The service is healthy", obj(
+			"type", "object",
+			"properties", obj("status", obj("type", "string"), "service", obj("type", "string"), "version", obj("type", "string")),
+		), nil)),
+	)))
+	tags := list(obj("name", "health", "description", "Service health"))
+
+	for _, table := range tables {
+		names := namesFor(table)
+		addOpenAPITableSchemas(schemas, table)
+		addOpenAPITableParameters(parameters, table)
+		addOpenAPITablePaths(paths, table)
+		tags = append(tags, obj("name", names.EntityPlural, "description", fmt.Sprintf("Records of the %s table", table.Name)))
+	}
+
+	securitySchemes := obj("bearerAuth", obj(
+		"type", "http",
+		"scheme", "bearer",
+		"bearerFormat", "JWT",
+		"description", "A JWT whose scopes and roles are checked against the access policy of each route",
+	))
+	security := list(obj("bearerAuth", list()))
+	if usesAPIKeys() {
+		securitySchemes.set("apiKeyAuth", obj(
+			"type", "apiKey",
+			"in", "header",
+			"name", "X-API-Key",
+			"description", "An API key issued with the api-key command",
+		))
+		security = append(security, obj("apiKeyAuth", list()))
+	}
+
+	components := obj(
+		"schemas", schemas,
+		"parameters", parameters,
+		"responses", openAPIErrorResponses(),
+	)
+	if slices.ContainsFunc(tables, usesVersioning) {
+		components.set("headers", obj("ETag", obj(
+			"description", "The version of the record as a strong entity tag",
+			"schema", obj("type", "string"),
+		)))
+	}
+	components.set("securitySchemes", securitySchemes)
+
+	return obj(
+		"openapi", "3.1.0",
+		"info", obj(
+			"title", moduleName,
+			"version", "1.0.0",
+			"description", fmt.Sprintf("REST API of %s. Generated from its SQL schema; regenerate instead of editing.", moduleName),
+		),
+		"servers", list(obj("url", "/")),
+		"security", security,
+		"tags", tags,
+		"paths", paths,
+		"components", components,
+	)
+}
+
+// errorSchemaName is the schema of error bodies in the configured response format
+func errorSchemaName() string {
+	if usesProblemDetails() {
+		return "Problem"
+	}
+	return "Error"
+}
+
+// errorMediaType is the content type of error bodies in the configured response format
+func errorMediaType() string {
+	if usesProblemDetails() {
+		return "application/problem+json"
+	}
+	return "application/json"
+}
+
+// openAPIErrorResponses lists the error responses operations refer to, named as by statusFor
+func openAPIErrorResponses() *oaObject {
+	responses := obj()
+	for _, response := range [][2]string{
+		{"BadRequest", "The request, its filters, sort or body are invalid"},
+		{"Unauthorized", "The request carries no valid credentials"},
+		{"Forbidden", "The principal lacks a required scope, role or tenant"},
+		{"NotFound", "The record does not exist"},
+		{"Conflict", "The change conflicts with another record, e.g. a unique column"},
+		{"PreconditionFailed", "If-Match does not name the current version of the record"},
+		{"PreconditionRequired", "The change does not name a version in If-Match"},
+		{"PayloadTooLarge", fmt.Sprintf("The batch holds more than %d items or %d bytes", batchMaxItems(), batchMaxBytes())},
+		{"UnprocessableEntity", "The change violates a rule of the database"},
+		{"GatewayTimeout", "The database did not answer in time"},
+		{"InternalError", "An unexpected error occurred"},
+	} {
+		responses.set(response[0], obj(
+			"description", response[1],
+			"content", obj(errorMediaType(), obj("schema", ref("schemas", errorSchemaName()))),
+		))
+	}
+	return responses
+}
+
+// openAPISharedSchemas defines the schemas every table shares
+func openAPISharedSchemas() *oaObject {
+	integer := obj("type", "integer", "format", "int64")
+	schemas := obj("FieldError", obj(
+		"type", "object",
+		"description", "A field that failed validation",
+		"required", list("field", "rule", "message"),
+		"properties", obj(
+			"field", obj("type", "string"),
+			"rule", obj("type", "string"),
+			"param", obj("type", "string"),
+			"message", obj("type", "string"),
+		),
+	))
+
+	if usesProblemDetails() {
+		schemas.set("Problem", obj(
+			"type", "object",
+			"description", "An RFC 7807 problem; errors lists the invalid fields of validation errors",
+			"required", list("type", "title", "status"),
+			"properties", obj(
+				"type", obj("type", "string"),
+				"title", obj("type", "string"),
+				"status", obj("type", "integer"),
+				"detail", obj("type", "string"),
+				"instance", obj("type", "string"),
+				"errors", obj("type", "array", "items", ref("schemas", "FieldError")),
+			),
+		))
+	} else {
+		schemas.set("Error", obj(
+			"type", "object",
+			"description", "An error in the response wrapper; data lists the invalid fields of validation errors",
+			"required", list("message", "error", "code"),
+			"properties", obj(
+				"data", obj("type", "array", "items", ref("schemas", "FieldError")),
+				"message", obj("type", "string"),
+				"error", obj("type", "string"),
+				"code", obj("type", "integer"),
+			),
+		))
+	}
+
+	schemas.set("PageMeta", obj(
+		"type", "object",
+		"description", "Pagination of an offset-paginated list",
+		"required", list("total", "limit", "page"),
+		"properties", obj("total", integer, "limit", integer, "page", integer),
+	))
+	schemas.set("CursorMeta", obj(
+		"type", "object",
+		"description", "Pagination of a keyset-paginated list; next_cursor is omitted on the last page",
+		"required", list("limit"),
+		"properties", obj("limit", obj("type", "integer"), "cursor", obj("type", "string"), "next_cursor", obj("type", "string")),
+	))
+	schemas.set("JSONPatch", obj(
+		"type", "array",
+		"description", "An RFC 6902 JSON Patch of top-level members",
+		"items", obj(
+			"type", "object",
+			"required", list("op", "path"),
+			"properties", obj(
+				"op", obj("type", "string", "enum", list("add", "replace", "remove")),
+				"path", obj("type", "string"),
+				"value", obj(),
+			),
+		),
+	))
+	schemas.set("BatchDeleteItem", obj(
+		"type", "object",
+		"description", "A record to delete; versioned records must name the version they expect",
+		"required", list("id"),
+		"properties", obj("id", obj("type", "integer", "format", "int64", "exclusiveMinimum", 0), "version", integer),
+	))
+	schemas.set("BatchItemResult", obj(
+		"type", "object",
+		"description", "The outcome of a batch item: 200 or 201 when applied, 424 when an atomic batch was rolled back",
+		"required", list("index", "status"),
+		"properties", obj(
+			"index", obj("type", "integer"),
+			"status", obj("type", "integer"),
+			"id", integer,
+			"error", obj("type", "string"),
+			"fields", obj("type", "array", "items", ref("schemas", "FieldError")),
+		),
+	))
+	schemas.set("ImportError", obj(
+		"type", "object",
+		"description", "A row that could not be imported, by its line in the body",
+		"required", list("line", "status", "error"),
+		"properties", obj(
+			"line", obj("type", "integer"),
+			"status", obj("type", "integer"),
+			"error", obj("type", "string"),
+			"fields", obj("type", "array", "items", ref("schemas", "FieldError")),
+		),
+	))
+	schemas.set("ImportReport", obj(
+		"type", "object",
+		"description", fmt.Sprintf("The outcome of an import; errors lists the first %d failed rows", batchMaxItems()),
+		"required", list("created", "failed"),
+		"properties", obj(
+			"created", obj("type", "integer"),
+			"failed", obj("type", "integer"),
+			"errors", obj("type", "array", "items", ref("schemas", "ImportError")),
+		),
+	))
+	if usesAuditLog() {
+		schemas.set("AuditEntry", obj(
+			"type", "object",
+			"description", "A change of a record and the fields it changed",
+			"required", list("id", "action", "actor", "before", "after", "created_at"),
+			"properties", obj(
+				"id", integer,
+				"action", obj("type", "string", "enum", list("create", "update", "delete", "restore", "purge")),
+				"actor", obj("type", "string"),
+				"before", obj("type", list("object", "null")),
+				"after", obj("type", list("object", "null")),
+				"created_at", obj("type", "string", "format", "date-time"),
+			),
+		))
+	}
+
+	// Filters take one value per operator, e.g. ?age[gte]=18
+	for _, kind := range []string{"Int64", "Float64", "Bool", "String"} {
+		value := filterValueSchema(kind)
+		schemas.set(kind+"Filter", obj(
+			"type", "object",
+			"properties", obj(
+				"eq", value, "ne", value, "gt", value, "gte", value, "lt", value, "lte", value,
+				"in", obj("type", "string", "description", "Comma-separated values"),
+				"between", obj("type", "string", "description", "Two comma-separated bounds"),
+				"like", obj("type", "string", "description", "A SQL LIKE pattern"),
+				"null", obj("type", "boolean", "description", "Whether the column is null"),
+			),
+		))
+	}
+	return schemas
+}
+
+// filterValueSchema is the schema of a filter value of the reflect kind used in rest_parameter.go
+func filterValueSchema(kind string) *oaObject {
+	switch kind {
+	case "Int64":
+		return obj("type", "integer", "format", "int64")
+	case "Float64":
+		return obj("type", "number", "format", "double")
+	case "Bool":
+		return obj("type", "boolean")
+	default:
+		return obj("type", "string")
+	}
+}
+
+// openAPISharedParameters defines the parameters the routes of several tables share
+func openAPISharedParameters(tables []Table) *oaObject {
+	parameters := obj(
+		"ID", obj("name", "id", "in", "path", "required", true, "schema", obj("type", "integer", "format", "int64")),
+		"Limit", obj("name", "limit", "in", "query", "description", "The page size",
+			"schema", obj("type", "integer", "minimum", 1, "maximum", maxPageSize(), "default", 10)),
+		"Offset", obj("name", "offset", "in", "query", "description", "The number of records to skip",
+			"schema", obj("type", "integer", "minimum", 0, "default", 0)),
+		"Format", obj("name", "format", "in", "query", "description", "The format of the rows",
+			"schema", obj("type", "string", "enum", list(formatCSV, formatNDJSON), "default", formatCSV)),
+	)
+	if slices.ContainsFunc(tables, usesCursorPagination) {
+		parameters.set("Cursor", obj("name", "cursor", "in", "query", "description", "The next_cursor of the previous page",
+			"schema", obj("type", "string")))
+	}
+	if slices.ContainsFunc(tables, usesSoftDelete) {
+		parameters.set("IncludeDeleted", obj("name", "include_deleted", "in", "query",
+			"description", "Adds soft-deleted records; requires an admin role of the table",
+			"schema", obj("type", "boolean", "default", false)))
+	}
+	if slices.ContainsFunc(tables, usesVersioning) {
+		parameters.set("IfMatch", obj("name", "If-Match", "in", "header", "required", true,
+			"description", `The ETag of the version the change replaces, or "*" for any version`,
+			"schema", obj("type", "string")))
+		parameters.set("IfNoneMatch", obj("name", "If-None-Match", "in", "header",
+			"description", "Answers 304 when the record still has one of these ETags",
+			"schema", obj("type", "string")))
+	}
+	if usesTenancy() && tenantSource() == tenantSourceHeader {
+		parameters.set("Tenant", obj("name", tenantHeader(), "in", "header", "required", true,
+			"description", "The tenant the request acts for",
+			"schema", obj("type", "string")))
+	}
+	return parameters
+}
+
+// formats of exports and imports, as in rest-transfer.template
+const (
+	formatCSV    = "csv"
+	formatNDJSON = "ndjson"
+)
+
+// columnSchema translates a column and the validator rules derived from the schema into a JSON schema
+func columnSchema(col Column) *oaObject {
+	schema := obj()
+	numeric := col.GoType == "int64" || col.GoType == "float64"
+	switch col.GoType {
+	case "int64":
+		schema.set("type", "integer").set("format", "int64")
+	case "float64":
+		schema.set("type", "number").set("format", "double")
+	case "bool":
+		schema.set("type", "boolean")
+	case "time.Time":
+		schema.set("type", "string").set("format", "date-time")
+	case "[]string":
+		schema.set("type", "array").set("items", obj("type", "string"))
+	default:
+		schema.set("type", "string")
+	}
+
+	for _, rule := range col.validationRules() {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "gt":
+			schema.set("exclusiveMinimum", json.Number(param))
+		case "gte":
+			schema.set("minimum", json.Number(param))
+		case "lt":
+			schema.set("exclusiveMaximum", json.Number(param))
+		case "lte":
+			schema.set("maximum", json.Number(param))
+		case "eq":
+			schema.set("const", json.Number(param))
+		case "ne":
+			schema.set("not", obj("const", json.Number(param)))
+		case "min":
+			schema.set("minLength", json.Number(param))
+		case "max":
+			schema.set("maxLength", json.Number(param))
+		case "email":
+			schema.set("format", "email")
+		case "url":
+			schema.set("format", "uri")
+		case "uuid":
+			schema.set("format", "uuid")
+		case "oneof":
+			var values []any
+			for _, value := range strings.Fields(param) {
+				if _, err := strconv.ParseFloat(value, 64); numeric && err == nil {
+					values = append(values, json.Number(value))
+				} else {
+					values = append(values, value)
+				}
+			}
+			schema.set("enum", values)
+		}
+	}
+	return schema
+}
+
+// nullable lets a column schema also accept null
+func nullable(schema *oaObject) *oaObject {
+	copied := obj()
+	for _, key := range schema.keys {
+		copied.set(key, schema.values[key])
+	}
+	copied.set("type", list(schema.values["type"], "null"))
+	return copied
+}
+
+// requestSchema describes a create or update request with the columns included by the filter,
+// listing those it requires
+func requestSchema(table Table, description string, include, requires func(Column) bool) *oaObject {
+	properties := obj()
+	var required []any
+	for _, col := range table.Columns {
+		if !include(col) {
+			continue
+		}
+		member := strings.ToLower(col.Name)
+		properties.set(member, columnSchema(col))
+		if requires(col) {
+			required = append(required, member)
+		}
+	}
+
+	schema := obj("type", "object", "description", description)
+	if required != nil {
+		schema.set("required", required)
+	}
+	return schema.set("properties", properties)
+}
+
+// addOpenAPITableSchemas defines the response, request and patch schemas of a table, as in its DTOs
+func addOpenAPITableSchemas(schemas *oaObject, table Table) {
+	names := namesFor(table)
+	timestamp := obj("type", "string", "format", "date-time")
+
+	properties := obj("id", obj("type", "integer", "format", "int64"))
+	required := list("id", "created_at", "updated_at")
+	for _, col := range table.Columns {
+		if col.inResponse() {
+			properties.set(strings.ToLower(col.Name), columnSchema(col))
+			required = append(required, strings.ToLower(col.Name))
+		}
+	}
+	properties.set("created_at", timestamp).set("updated_at", timestamp)
+	if usesVersioning(table) {
+		properties.set("version", obj("type", "integer", "format", "int64"))
+		required = append(required, "version")
+	}
+	if usesSoftDelete(table) {
+		properties.set("deleted_at", nullable(timestamp))
+		required = append(required, "deleted_at")
+	}
+	if usesAuditColumns() {
+		properties.set("created_by", obj("type", "string")).set("updated_by", obj("type", "string"))
+		required = append(required, "created_by", "updated_by")
+	}
+	schemas.set(names.Struct, obj(
+		"type", "object",
+		"description", fmt.Sprintf("A %s record", names.Entity),
+		"required", required,
+		"properties", properties,
+	))
+
+	schemas.set("Create"+names.Struct+"Request", requestSchema(table,
+		fmt.Sprintf("The body to create a %s; columns left out keep their database defaults", names.Entity),
+		Column.inCreateRequest, Column.requiresValue))
+	schemas.set("Update"+names.Struct+"Request", requestSchema(table,
+		fmt.Sprintf("The body to replace a %s", names.Entity),
+		Column.inUpdateRequest, Column.requiredOnUpdate))
+
+	// Upserts match rows by ID unless the table names another key, and replace versioned rows at the named version
+	upsert := obj()
+	if upsertKeyFor(table)[0].IsPrimaryKey {
+		upsert.set("id", obj("type", "integer", "format", "int64", "exclusiveMinimum", 0))
+	}
+	if usesVersioning(table) {
+		upsert.set("version", obj("type", "integer", "format", "int64", "exclusiveMinimum", 0))
+	}
+	create := ref("schemas", "Create"+names.Struct+"Request")
+	if len(upsert.keys) == 0 {
+		schemas.set("Upsert"+names.Struct+"Request", create)
+	} else {
+		schemas.set("Upsert"+names.Struct+"Request", obj(
+			"description", fmt.Sprintf("A new %s, or the replacement of the one with the same %s", names.Entity, upsertKeyDoc(table)),
+			"allOf", list(create, obj("type", "object", "properties", upsert)),
+		))
+	}
+
+	patch := obj()
+	for _, col := range table.Columns {
+		if !col.inUpdateRequest() {
+			continue
+		}
+		schema := columnSchema(col)
+		if col.IsNullable {
+			schema = nullable(schema)
+		}
+		patch.set(strings.ToLower(col.Name), schema)
+	}
+	schemas.set(names.Struct+"Patch", obj(
+		"type", "object",
+		"description", fmt.Sprintf("An RFC 7396 merge patch of a %s; null clears a nullable column", names.Entity),
+		"additionalProperties", false,
+		"properties", patch,
+	))
+}
+
+// addOpenAPITableParameters defines the filter and sort parameters of a table, as in rest_parameter.go
+func addOpenAPITableParameters(parameters *oaObject, table Table) {
+	names := namesFor(table)
+	var sortable []string
+	for _, col := range queryColumnsFor(table) {
+		parameters.set(fmt.Sprintf("%sFilter.%s", names.Struct, col.Name), obj(
+			"name", col.Name,
+			"in", "query",
+			"description", fmt.Sprintf("Filters by %s, one operator per key as in ?%s[ne]=x; ?%s=x compares for equality",
+				col.Name, col.Name, col.Name),
+			"style", "deepObject",
+			"explode", true,
+			"schema", ref("schemas", col.Kind+"Filter"),
+		))
+		sortable = append(sortable, col.Name)
+	}
+	parameters.set(names.Struct+"Sort", obj(
+		"name", "sort",
+		"in", "query",
+		"description", "Comma-separated columns to sort by, a leading - sorts descending. Sortable: "+strings.Join(sortable, ", "),
+		"schema", obj("type", "string"),
+	))
+}
+
+// okResponse describes a successful response in the configured body format
+func okResponse(description string, data, meta any) *oaObject {
+	properties := obj("data", data)
+	required := list()
+	if meta != nil {
+		properties.set("meta", meta)
+		required = append(required, "meta")
+	}
+	if !usesProblemDetails() {
+		properties.set("message", obj("type", "string")).set("code", obj("type", "integer"))
+		required = append(required, "message", "code")
+	}
+
+	schema := obj("type", "object")
+	if len(required) > 0 {
+		schema.set("required", required)
+	}
+	return obj(
+		"description", description,
+		"content", obj("application/json", obj("schema", schema.set("properties", properties))),
+	)
+}
+
+// rolesNote describes the roles an operation requires
+func rolesNote(roles []string) string {
+	if len(roles) == 0 {
+		return "Open to every authenticated principal."
+	}
+	return "Requires one of the roles: " + strings.Join(roles, ", ") + "."
+}
+
+// tableOperation builds an operation of a table route with its error responses
+func tableOperation(table Table, id, summary string, roles []string, errors ...string) *oaObject {
+	operation := obj(
+		"tags", list(namesFor(table).EntityPlural),
+		"operationId", id,
+		"summary", summary,
+		"description", rolesNote(roles),
+		"responses", obj(),
+	)
+	withErrors(operation, append([]string{"Unauthorized", "Forbidden", "InternalError", "GatewayTimeout"}, errors...)...)
+	if usesTenancy() && tenantSource() == tenantSourceHeader {
+		if _, ok := tenantColumnFor(table); ok {
+			withParameters(operation, ref("parameters", "Tenant"))
+		}
+	}
+	return operation
+}
+
+// errorStatus maps the error responses to their status codes
+var errorStatus = map[string]string{
+	"BadRequest": "400", "Unauthorized": "401", "Forbidden": "403", "NotFound": "404", "Conflict": "409",
+	"PreconditionFailed": "412", "PayloadTooLarge": "413", "UnprocessableEntity": "422", "PreconditionRequired": "428",
+	"InternalError": "500", "GatewayTimeout": "504",
+}
+
+// withErrors adds error responses to an operation
+func withErrors(operation *oaObject, names ...string) *oaObject {
+	for _, name := range names {
+		withResponse(operation, errorStatus[name], ref("responses", name))
+	}
+	return operation
+}
+
+// withParameters appends parameters to an operation
+func withParameters(operation *oaObject, parameters ...any) *oaObject {
+	existing, _ := operation.values["parameters"].([]any)
+	return operation.setBefore("parameters", append(existing, parameters...), "responses")
+}
+
+// withResponse adds a response to an operation, keeping the responses ordered by status
+func withResponse(operation *oaObject, status string, response *oaObject) *oaObject {
+	responses := operation.values["responses"].(*oaObject)
+	responses.set(status, response)
+	slices.Sort(responses.keys)
+	return operation
+}
+
+// withBody sets the JSON request body of an operation
+func withBody(operation *oaObject, schema any) *oaObject {
+	return operation.setBefore("requestBody", obj(
+		"required", true,
+		"content", obj("application/json", obj("schema", schema)),
+	), "responses")
+}
+
+// withETag adds the ETag header to the successful response of an operation on a versioned record
+func withETag(operation *oaObject, status string) *oaObject {
+	response := operation.values["responses"].(*oaObject).values[status].(*oaObject)
+	response.set("headers", obj("ETag", ref("headers", "ETag")))
+	return operation
+}
+
+// addOpenAPITablePaths describes the routes registered for a table by rest-routes.template
+func addOpenAPITablePaths(paths *oaObject, table Table) {
+	names := namesFor(table)
+	access := accessFor(table)
+	collection := "/" + names.EntityPlural
+	item := collection + "/{id}"
+	record := ref("schemas", names.Struct)
+	records := obj("type", "array", "items", record)
+	deleted := obj("type", "object", "properties", obj("id", obj("type", "integer", "format", "int64")))
+
+	var filters []any
+	for _, col := range queryColumnsFor(table) {
+		filters = append(filters, ref("parameters", fmt.Sprintf("%sFilter.%s", names.Struct, col.Name)))
+	}
+	sort := ref("parameters", names.Struct+"Sort")
+	listed := filters
+	if usesSoftDelete(table) {
+		listed = append(append([]any{}, filters...), ref("parameters", "IncludeDeleted"))
+	}
+
+	// Collection routes
+	listing := tableOperation(table, "list"+names.Plural, "List "+names.EntityPlural, access.Read, "BadRequest")
+	if usesCursorPagination(table) {
+		withParameters(listing, ref("parameters", "Limit"), ref("parameters", "Cursor"))
+		withParameters(listing, listed...)
+		withResponse(listing, "200", okResponse("A page of "+names.EntityPlural, obj(
+			"type", "object",
+			"required", []any{"items", "meta"},
+			"properties", obj("items", records, "meta", ref("schemas", "CursorMeta")),
+		), nil))
+	} else {
+		withParameters(listing, ref("parameters", "Limit"), ref("parameters", "Offset"), sort)
+		withParameters(listing, listed...)
+		withResponse(listing, "200", okResponse("A page of "+names.EntityPlural, records, ref("schemas", "PageMeta")))
+	}
+
+	create := tableOperation(table, "create"+names.Struct, "Create a "+names.Entity, access.Write, "BadRequest", "Conflict", "UnprocessableEntity")
+	withBody(create, ref("schemas", "Create"+names.Struct+"Request"))
+	withResponse(create, "201", okResponse("The ID of the created "+names.Entity, obj("type", "integer", "format", "int64"), nil))
+	paths.set(collection, obj("get", listing, "post", create))
+
+	export := tableOperation(table, "export"+names.Plural, "Export "+names.EntityPlural, access.Read, "BadRequest")
+	withParameters(export, ref("parameters", "Format"), sort)
+	withParameters(export, listed...)
+	withResponse(export, "200", obj(
+		"description", "Every "+names.Entity+" matching the filters, streamed without pagination",
+		"headers", obj("Content-Disposition", obj("schema", obj("type", "string"))),
+		"content", obj(
+			"text/csv", obj("schema", obj("type", "string")),
+			"application/x-ndjson", obj("schema", obj("type", "string")),
+		),
+	))
+	paths.set(collection+"/export", obj("get", export))
+
+	importOp := tableOperation(table, "import"+names.Plural, "Import "+names.EntityPlural, access.Write, "BadRequest")
+	withParameters(importOp, ref("parameters", "Format"))
+	importOp.setBefore("requestBody", obj(
+		"required", true,
+		"description", "Rows of Create"+names.Struct+"Request; CSV starts with a header line of member names",
+		"content", obj(
+			"text/csv", obj("schema", obj("type", "string")),
+			"application/x-ndjson", obj("schema", obj("type", "string")),
+		),
+	), "responses")
+	withResponse(importOp, "207", okResponse("Some rows failed", ref("schemas", "ImportReport"), nil))
+	withResponse(importOp, "200", okResponse("Every row was created", ref("schemas", "ImportReport"), nil))
+	paths.set(collection+"/import", obj("post", importOp))
+
+	// Record routes
+	get := tableOperation(table, "get"+names.Struct, "Get a "+names.Entity, access.Read, "BadRequest", "NotFound")
+	withResponse(get, "200", okResponse("The "+names.Entity, record, nil))
+	update := tableOperation(table, "update"+names.Struct, "Replace a "+names.Entity, access.Write,
+		"BadRequest", "NotFound", "Conflict", "UnprocessableEntity")
+	withBody(update, ref("schemas", "Update"+names.Struct+"Request"))
+	withResponse(update, "200", okResponse("The updated "+names.Entity, record, nil))
+	patch := tableOperation(table, "patch"+names.Struct, "Partially update a "+names.Entity, access.Write,
+		"BadRequest", "NotFound", "Conflict", "UnprocessableEntity")
+	patch.setBefore("requestBody", obj(
+		"required", true,
+		"content", obj(
+			"application/merge-patch+json", obj("schema", ref("schemas", names.Struct+"Patch")),
+			"application/json", obj("schema", ref("schemas", names.Struct+"Patch")),
+			"application/json-patch+json", obj("schema", ref("schemas", "JSONPatch")),
+		),
+	), "responses")
+	withResponse(patch, "200", okResponse("The patched "+names.Entity, record, nil))
+	remove := tableOperation(table, "delete"+names.Struct, "Delete a "+names.Entity, access.Delete, "BadRequest", "NotFound")
+	withResponse(remove, "200", okResponse("The ID of the deleted "+names.Entity, deleted, nil))
+
+	// Changes of versioned records must name the version they replace
+	if usesVersioning(table) {
+		withParameters(get, ref("parameters", "IfNoneMatch"))
+		withETag(get, "200")
+		withResponse(get, "304", obj("description", "The record still has the ETag named by If-None-Match"))
+		for _, change := range []*oaObject{update, patch, remove} {
+			withParameters(change, ref("parameters", "IfMatch"))
+			withErrors(change, "PreconditionFailed", "PreconditionRequired")
+		}
+		withETag(update, "200")
+		withETag(patch, "200")
+	}
+	paths.set(item, obj(
+		"parameters", []any{ref("parameters", "ID")},
+		"get", get,
+		"put", update,
+		"patch", patch,
+		"delete", remove,
+	))
+
+	if usesAuditLog() {
+		history := tableOperation(table, "get"+names.Struct+"History", "Get the audit log of a "+names.Entity, access.Read, "BadRequest", "NotFound")
+		withResponse(history, "200", okResponse("The changes of the "+names.Entity+", oldest first",
+			obj("type", "array", "items", ref("schemas", "AuditEntry")), nil))
+		paths.set(item+"/history", obj("parameters", []any{ref("parameters", "ID")}, "get", history))
+	}
+	if usesSoftDelete(table) {
+		restore := tableOperation(table, "restore"+names.Struct, "Restore a soft-deleted "+names.Entity, adminRolesFor(table), "BadRequest", "NotFound", "Conflict")
+		withResponse(restore, "200", okResponse("The restored "+names.Entity, record, nil))
+		paths.set(item+"/restore", obj("parameters", []any{ref("parameters", "ID")}, "post", restore))
+
+		purge := tableOperation(table, "purge"+names.Struct, "Permanently delete a "+names.Entity, adminRolesFor(table), "BadRequest", "NotFound")
+		withResponse(purge, "200", okResponse("The ID of the purged "+names.Entity, deleted, nil))
+		paths.set(item+"/purge", obj("parameters", []any{ref("parameters", "ID")}, "delete", purge))
+	}
+
+	// Batches answer 207 Multi-Status when any item failed
+	for _, batch := range []struct {
+		method, summary string
+		items           *oaObject
+		roles           []string
+	}{
+		{"batchCreate", "Create " + names.EntityPlural + " in a batch", ref("schemas", "Create"+names.Struct+"Request"), access.Write},
+		{"batchUpsert", "Create or replace " + names.EntityPlural + " in a batch", ref("schemas", "Upsert"+names.Struct+"Request"), access.Write},
+		{"batchDelete", "Delete " + names.EntityPlural + " in a batch", ref("schemas", "BatchDeleteItem"), access.Delete},
+	} {
+		operation := tableOperation(table, batch.method+names.Plural, batch.summary, batch.roles, "BadRequest", "PayloadTooLarge")
+		withBody(operation, obj(
+			"type", "object",
+			"required", []any{"items"},
+			"properties", obj(
+				"items", obj("type", "array", "items", batch.items, "minItems", 1, "maxItems", batchMaxItems()),
+				"atomic", obj("type", "boolean", "description", "Applies every item or none", "default", false),
+			),
+		))
+		results := obj("type", "array", "items", ref("schemas", "BatchItemResult"))
+		withResponse(operation, "207", okResponse("Some items failed", results, nil))
+		withResponse(operation, "200", okResponse("Every item was applied", results, nil))
+		paths.set(collection+":"+batch.method, obj("post", operation))
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -15,20 +16,18 @@ func generateRestAPIMain(moduleName string, tables []Table) string {
 
 	// Only import interactor package if there are tables
 	if len(tables) > 0 {
-		interactorImport = fmt.Sprintf("\n\n\t\"%s/internal/interactor\"", moduleName)
+		interactorImport = fmt.Sprintf("\n\t\"%s/internal/interactor\"", moduleName)
 	}
 
 	// Generate service interfaces and initialization for each table
 	for i, table := range tables {
-		structName := toCamelCase(table.Name)
-		if strings.HasSuffix(structName, "s") {
-			structName = structName[:len(structName)-1]
-		}
+		names := namesFor(table)
+		structName := names.Struct
 
 		interfaceName := fmt.Sprintf("interactor.I%sService", structName)
-		fieldName := fmt.Sprintf("%sService", strings.ToLower(structName))
-		entityName := strings.ToLower(structName)
-		entityPlural := entityName + "s"
+		fieldName := fmt.Sprintf("%sService", names.Entity)
+		entityName := names.Entity
+		entityPlural := names.EntityPlural
 
 		serviceFields.WriteString(fmt.Sprintf("\t%s %s\n", fieldName, interfaceName))
 
@@ -40,12 +39,41 @@ func generateRestAPIMain(moduleName string, tables []Table) string {
 		serviceInit.WriteString(fmt.Sprintf("\t\t%s: %s,\n", fieldName, fieldName))
 
 		// Generate route registrations using template
+		access := accessFor(table)
 		routeVars := map[string]string{
 			"struct_name":   structName,
 			"entity_name":   entityName,
 			"entity_plural": entityPlural,
-			"plural_name":   structName + "s",
+			"plural_name":   names.Plural,
 			"field_name":    fieldName,
+			"read_roles":    "[]string{" + quotedList(access.Read) + "}",
+			"write_roles":   "[]string{" + quotedList(access.Write) + "}",
+			"delete_roles":  "[]string{" + quotedList(access.Delete) + "}",
+			"authenticate":  authenticateMiddleware(),
+			"admin_roles":   "[]string{" + quotedList(adminRolesFor(table)) + "}",
+			"tenant_open":   "",
+			"tenant_close":  "",
+			"list_open":     "",
+			"list_close":    "",
+			"change_open":   "",
+			"change_close":  "",
+		}
+
+		if _, ok := tenantColumnFor(table); ok {
+			routeVars["tenant_open"] = "r.WithTenant("
+			routeVars["tenant_close"] = ")"
+		}
+
+		// Administrators may add soft-deleted records to listings
+		if usesSoftDelete(table) {
+			routeVars["list_open"] = "r.IncludeDeleted("
+			routeVars["list_close"] = ", " + routeVars["admin_roles"] + ")"
+		}
+
+		// Changes to versioned records must name the version they replace
+		if usesVersioning(table) {
+			routeVars["change_open"] = "r.IfMatch("
+			routeVars["change_close"] = ")"
 		}
 
 		routeResult, err := processTemplate("rest-routes", routeVars)
@@ -54,22 +82,37 @@ func generateRestAPIMain(moduleName string, tables []Table) string {
 		}
 		routeRegistrations.WriteString("\n")
 		routeRegistrations.WriteString(routeResult)
-	}
-
-	serviceParamsStr := ""
-	if serviceParams.Len() > 0 {
-		serviceParamsStr = ", " + serviceParams.String()
+		if usesAuditLog() {
+			routeRegistrations.WriteString("\n")
+			routeRegistrations.WriteString(strings.TrimSuffix(mustProcessTemplate("rest-routes-history", routeVars), "\n"))
+		}
+		if usesSoftDelete(table) {
+			routeRegistrations.WriteString("\n")
+			routeRegistrations.WriteString(strings.TrimSuffix(mustProcessTemplate("rest-routes-restore", routeVars), "\n"))
+		}
+		routeRegistrations.WriteString("\n")
+		routeRegistrations.WriteString(strings.TrimSuffix(mustProcessTemplate("rest-routes-batch", routeVars), "\n"))
 	}
 
 	vars := map[string]string{
 		"module_name":         moduleName,
+		"auth_fields":         "",
+		"auth_params":         "",
+		"auth_init":           "",
 		"service_fields":      serviceFields.String(),
-		"service_params":      serviceParamsStr,
+		"service_params":      serviceParams.String(),
 		"service_init":        serviceInit.String(),
 		"route_registrations": routeRegistrations.String(),
 		"interactor_import":   interactorImport,
 	}
 
+	// API keys are checked by their own middleware in front of Authenticate
+	if usesAPIKeys() {
+		vars["auth_fields"] = "\n\tapiKeys  *auth.APIKeyVerifier"
+		vars["auth_params"] = "apiKeys *auth.APIKeyVerifier, "
+		vars["auth_init"] = "\n\t\tapiKeys:  apiKeys,"
+	}
+
 	result, err := processTemplate("rest-api-main", vars)
 	if err != nil {
 		panic(fmt.Sprintf("Error processing rest-api-main template: %v", err))
@@ -77,19 +120,33 @@ func generateRestAPIMain(moduleName string, tables []Table) string {
 	return result
 }
 
+// authenticateMiddleware returns the API method that authenticates entity routes
+func authenticateMiddleware() string {
+	if usesAPIKeys() {
+		return "AuthenticateAPIKey"
+	}
+	return "Authenticate"
+}
+
 // generateRestHandler creates REST handler for individual table
 func generateRestHandler(moduleName string, table Table) string {
-	structName := toCamelCase(table.Name)
-	if strings.HasSuffix(structName, "s") {
-		structName = structName[:len(structName)-1]
-	}
+	names := namesFor(table)
+	structName := names.Struct
 
-	entityName := strings.ToLower(structName)
-	entityPlural := entityName + "s"
+	entityName := names.Entity
+	entityPlural := names.EntityPlural
 	singularName := structName
-	pluralName := structName + "s"
+	pluralName := names.Plural
 	dtoName := structName
-	entityVar := entityName
+	entityVar := names.Var
+
+	// Extra imports needed by optional handler variants
+	getAllTemplate := "rest-func-get-all"
+	stdImports := ""
+	moduleImports := ""
+	if usesCursorPagination(table) {
+		getAllTemplate = "rest-func-get-all-cursor"
+	}
 
 	vars := map[string]string{
 		"module_name":     moduleName,
@@ -101,6 +158,19 @@ func generateRestHandler(moduleName string, table Table) string {
 		"plural_name":     pluralName,
 		"dto_name":        dtoName,
 		"entity_var":      entityVar,
+		"plural_var":      names.PluralVar,
+		"std_imports":     stdImports,
+		"module_imports":  moduleImports,
+	}
+
+	// Versioned records carry their version as ETag
+	vars["not_modified"] = ""
+	vars["update_etag"] = ""
+	vars["patch_etag"] = ""
+	if usesVersioning(table) {
+		vars["not_modified"] = fmt.Sprintf("\n\tif notModified(w, r, %s.Version) {\n\t\treturn\n\t}\n", entityVar)
+		vars["update_etag"] = "\n\tw.Header().Set(\"ETag\", etag(updated.Version))"
+		vars["patch_etag"] = fmt.Sprintf("\n\tw.Header().Set(\"ETag\", etag(%s.Version))", entityVar)
 	}
 
 	var handler strings.Builder
@@ -113,9 +183,9 @@ func generateRestHandler(moduleName string, table Table) string {
 	handler.WriteString(headerResult)
 
 	// Individual handler methods
-	getAllResult, err := processTemplate("rest-func-get-all", vars)
+	getAllResult, err := processTemplate(getAllTemplate, vars)
 	if err != nil {
-		panic(fmt.Sprintf("Error processing rest-func-get-all template: %v", err))
+		panic(fmt.Sprintf("Error processing %s template: %v", getAllTemplate, err))
 	}
 	handler.WriteString("\n")
 	handler.WriteString(getAllResult)
@@ -141,6 +211,13 @@ func generateRestHandler(moduleName string, table Table) string {
 	handler.WriteString("\n")
 	handler.WriteString(updateResult)
 
+	patchResult, err := processTemplate("rest-func-patch", vars)
+	if err != nil {
+		panic(fmt.Sprintf("Error processing rest-func-patch template: %v", err))
+	}
+	handler.WriteString("\n")
+	handler.WriteString(patchResult)
+
 	deleteResult, err := processTemplate("rest-func-delete", vars)
 	if err != nil {
 		panic(fmt.Sprintf("Error processing rest-func-delete template: %v", err))
@@ -148,15 +225,28 @@ func generateRestHandler(moduleName string, table Table) string {
 	handler.WriteString("\n")
 	handler.WriteString(deleteResult)
 
+	if usesAuditLog() {
+		handler.WriteString("\n\n")
+		handler.WriteString(mustProcessTemplate("rest-func-history", vars))
+	}
+	if usesSoftDelete(table) {
+		handler.WriteString("\n\n")
+		handler.WriteString(mustProcessTemplate("rest-func-restore", vars))
+	}
+	handler.WriteString("\n\n")
+	handler.WriteString(mustProcessTemplate("rest-func-batch", vars))
+	handler.WriteString("\n\n")
+	handler.WriteString(mustProcessTemplate("rest-func-transfer", vars))
+
 	return handler.String()
 }
 
 // generateRestParameter creates the REST parameter file for filtering and sorting
-func generateRestParameter(tables []Table) string {
+func generateRestParameter(moduleName string, tables []Table) string {
 	var allContent strings.Builder
 
 	// Add package header and imports using template
-	headerResult, err := processTemplate("rest-parameter-header", map[string]string{})
+	headerResult, err := processTemplate("rest-parameter-header", map[string]string{"module_name": moduleName})
 	if err != nil {
 		panic(fmt.Sprintf("Error processing rest-parameter-header template: %v", err))
 	}
@@ -164,42 +254,14 @@ func generateRestParameter(tables []Table) string {
 
 	// Generate filter and sorting variables for each table
 	for i, table := range tables {
-		structName := toCamelCase(table.Name)
-		if strings.HasSuffix(structName, "s") {
-			structName = structName[:len(structName)-1]
-		}
-
-		entitySnake := strings.ToLower(structName)
+		entitySnake := namesFor(table).Entity
 
-		// Generate filter fields
+		// Generate filter and sorting fields
 		var filterFields strings.Builder
 		var sortingFields strings.Builder
-
-		// Add ID field first
-		filterFields.WriteString("\n\t\t{Omitempty: true, DBKey: \"id\", Kind: reflect.Int64, QueryKey: \"id\"},")
-		sortingFields.WriteString("\n\t\t{DBKey: \"id\", QueryKey: \"id\", Kind: reflect.Int64},")
-
-		// Add other fields
-		for _, col := range table.Columns {
-			if strings.ToLower(col.Name) == "id" ||
-				strings.ToLower(col.Name) == "created_at" ||
-				strings.ToLower(col.Name) == "updated_at" ||
-				strings.ToLower(col.Name) == "deleted_at" ||
-				strings.ToLower(col.Name) == "is_deleted" {
-				continue
-			}
-
-			reflectType := "reflect.String"
-			if col.GoType == "int64" {
-				reflectType = "reflect.Int64"
-			} else if col.GoType == "float64" {
-				reflectType = "reflect.Float64"
-			} else if col.GoType == "bool" {
-				reflectType = "reflect.Bool"
-			}
-
-			filterFields.WriteString(fmt.Sprintf("\n\t\t{Omitempty: true, DBKey: \"%s\", Kind: %s, QueryKey: \"%s\"},", col.Name, reflectType, col.Name))
-			sortingFields.WriteString(fmt.Sprintf("\n\t\t{DBKey: \"%s\", QueryKey: \"%s\", Kind: %s},", col.Name, col.Name, reflectType))
+		for _, col := range queryColumnsFor(table) {
+			filterFields.WriteString(fmt.Sprintf("\n\t\t{QueryKey: \"%s\", DBKey: \"%s\", Kind: reflect.%s},", col.Name, col.Name, col.Kind))
+			sortingFields.WriteString(fmt.Sprintf("\n\t\t{QueryKey: \"%s\", DBKey: \"%s\", Kind: reflect.%s},", col.Name, col.Name, col.Kind))
 		}
 
 		// Process template for this table
@@ -227,3 +289,188 @@ func generateRestParameter(tables []Table) string {
 
 	return allContent.String()
 }
+
+// queryColumn is a column list endpoints filter and sort on
+type queryColumn struct {
+	Name string
+	// Kind is the reflect kind its query values are parsed as: Int64, Float64, Bool or String
+	Kind string
+}
+
+// queryColumnsFor lists the columns a table's list endpoints filter and sort on: id first, then the
+// readable table columns, then the timestamps
+func queryColumnsFor(table Table) []queryColumn {
+	columns := []queryColumn{{Name: "id", Kind: "Int64"}}
+	for _, col := range table.Columns {
+		if metaColumns[strings.ToLower(col.Name)] || col.IsWriteOnly {
+			continue
+		}
+
+		kind := "String"
+		switch col.GoType {
+		case "int64":
+			kind = "Int64"
+		case "float64":
+			kind = "Float64"
+		case "bool":
+			kind = "Bool"
+		}
+		columns = append(columns, queryColumn{Name: col.Name, Kind: kind})
+	}
+
+	timestamps := []string{"created_at", "updated_at"}
+	if usesSoftDelete(table) {
+		timestamps = append(timestamps, "deleted_at")
+	}
+	for _, name := range timestamps {
+		columns = append(columns, queryColumn{Name: name, Kind: "String"})
+	}
+	return columns
+}
+
+// generateRestErrors creates the shared REST error responses
+func generateRestErrors(moduleName string) string {
+	vars := map[string]string{
+		"module_name": moduleName,
+	}
+
+	result, err := processTemplate("rest-errors", vars)
+	if err != nil {
+		panic(fmt.Sprintf("Error processing rest-errors template: %v", err))
+	}
+	return result
+}
+
+// generateRestBatch creates the shared REST batch decoding and responses
+func generateRestBatch(moduleName string) string {
+	vars := map[string]string{
+		"module_name": moduleName,
+		"max_items":   strconv.Itoa(batchMaxItems()),
+		"max_bytes":   strconv.FormatInt(batchMaxBytes(), 10),
+	}
+	return mustProcessTemplate("rest-batch", vars)
+}
+
+// generateRestTransfer creates the shared REST export and import streaming
+func generateRestTransfer(moduleName string) string {
+	return mustProcessTemplate("rest-transfer", map[string]string{"module_name": moduleName})
+}
+
+// generateRestBatchTest creates the batch decoding and response tests
+func generateRestBatchTest(moduleName string) string {
+	return mustProcessTemplate("rest-batch-test", map[string]string{"module_name": moduleName})
+}
+
+// generateRestTransferTest creates the export and import streaming tests
+func generateRestTransferTest(moduleName string) string {
+	return mustProcessTemplate("rest-transfer-test", map[string]string{"module_name": moduleName})
+}
+
+// generateRestAuthTest creates the Authenticate middleware tests
+func generateRestAuthTest(moduleName string) string {
+	vars := map[string]string{
+		"module_name": moduleName,
+	}
+
+	result, err := processTemplate("rest-auth-test", vars)
+	if err != nil {
+		panic(fmt.Sprintf("Error processing rest-auth-test template: %v", err))
+	}
+	return result
+}
+
+// generateRestTenant creates the middleware that scopes requests to the tenant of the configured source
+func generateRestTenant(moduleName string) string {
+	var source, lookup string
+	switch tenantSource() {
+	case tenantSourceHeader:
+		source = "the " + tenantHeader() + " header"
+		lookup = fmt.Sprintf("\t\ttenant := req.Header.Get(%q)", tenantHeader())
+	case tenantSourceSubdomain:
+		source = "the subdomain of the request"
+		lookup = "\t\ttenant := tenancy.Subdomain(req.Host)"
+	default:
+		source = "the tenant claim of the principal"
+		lookup = "\t\tvar tenant string\n\t\tif authenticated {\n\t\t\ttenant = principal.Tenant\n\t\t}"
+	}
+
+	vars := map[string]string{
+		"module_name":   moduleName,
+		"tenant_source": source,
+		"tenant_lookup": lookup,
+	}
+
+	result, err := processTemplate("rest-tenant", vars)
+	if err != nil {
+		panic(fmt.Sprintf("Error processing rest-tenant template: %v", err))
+	}
+	return result
+}
+
+// generateRestTenantTest creates the tests of the tenant middleware for the configured tenant source
+func generateRestTenantTest(moduleName string) string {
+	var request, cases string
+	switch tenantSource() {
+	case tenantSourceHeader:
+		request = fmt.Sprintf("\tif tenant != \"\" {\n\t\treq.Header.Set(%q, tenant)\n\t}", tenantHeader())
+	case tenantSourceSubdomain:
+		request = "\tif tenant != \"\" {\n\t\treq.Host = tenant + \".api.example.com\"\n\t}"
+	default:
+		// The claim names the tenant, so a request cannot name a tenant other than the principal's
+		request = "\tif principalTenant == \"\" {\n\t\tprincipalTenant = tenant\n\t}"
+	}
+	if tenantSource() != tenantSourceClaim {
+		cases = "\t\t{\"tenant of another principal\", \"acme\", \"globex\", http.StatusForbidden},\n"
+	}
+
+	vars := map[string]string{
+		"module_name":    moduleName,
+		"tenant_request": request,
+		"tenant_cases":   cases,
+	}
+	return mustProcessTemplate("rest-tenant-test", vars)
+}
+
+// generateRestResponse creates the response helpers in the configured body format
+func generateRestResponse(moduleName string) string {
+	templateName := "rest-response-wrapper"
+	if usesProblemDetails() {
+		templateName = "rest-response-problem"
+	}
+
+	vars := map[string]string{
+		"module_name": moduleName,
+	}
+
+	result, err := processTemplate(templateName, vars)
+	if err != nil {
+		panic(fmt.Sprintf("Error processing %s template: %v", templateName, err))
+	}
+	return result
+}
+
+// generateResponsePackage creates the self-contained RFC 7807 response package
+func generateResponsePackage(moduleName string) string {
+	vars := map[string]string{
+		"module_name": moduleName,
+	}
+
+	result, err := processTemplate("response", vars)
+	if err != nil {
+		panic(fmt.Sprintf("Error processing response template: %v", err))
+	}
+	return result
+}
+
+// generateRestQuery creates the query string parser for filters and sorting
+func generateRestQuery(moduleName string) string {
+	vars := map[string]string{
+		"module_name": moduleName,
+	}
+
+	result, err := processTemplate("rest-query", vars)
+	if err != nil {
+		panic(fmt.Sprintf("Error processing rest-query template: %v", err))
+	}
+	return result
+}
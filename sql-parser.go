@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -12,6 +13,8 @@ import (
 type Table struct {
 	Name    string
 	Columns []Column
+	// Constraints holds table-level constraint lines, re-emitted verbatim in migrations
+	Constraints []string
 }
 
 // Column represents a database column
@@ -24,6 +27,54 @@ type Column struct {
 	GoType       string
 	GormTag      string
 	JSONTag      string
+	FieldName    string
+
+	// GeneratedExpr holds the GENERATED clause of generated and identity columns
+	GeneratedExpr string
+	IsGenerated   bool
+	// IsReadOnly marks columns annotated "-- @readonly": set by clients once on create, never changed afterwards
+	IsReadOnly bool
+	// IsWriteOnly marks columns annotated "-- @writeonly": never returned to clients
+	IsWriteOnly bool
+	// IsTenant marks the tenant column, which the repository sets from the request and never from clients
+	IsTenant bool
+
+	// IsUnique marks columns declared UNIQUE inline
+	IsUnique bool
+	// Length is the declared size of VARCHAR(n) and CHAR(n) columns
+	Length int
+	// Checks holds the CHECK expressions that apply to the column, inline or table-level
+	Checks []string
+	// InlineCheck is the column's own CHECK clause, re-emitted in migrations
+	InlineCheck string
+	// EnumValues lists the labels of columns typed with a CREATE TYPE ... AS ENUM type
+	EnumValues []string
+
+	// RefTable and RefColumn name the column a foreign key column references, inline or table-level
+	RefTable  string
+	RefColumn string
+}
+
+// isMeta reports whether the column is provided by MetaField, or by AuditField when audit columns are enabled
+func (c Column) isMeta() bool {
+	name := strings.ToLower(c.Name)
+	return metaColumns[name] || (usesAuditColumns() && auditColumns[name])
+}
+
+// inCreateRequest reports whether clients may set the column when creating a record. This is the
+// only request read-only columns are accepted in, and server-defaulted columns are optional.
+func (c Column) inCreateRequest() bool {
+	return !c.isMeta() && !c.IsPrimaryKey && !c.IsGenerated && !c.IsTenant
+}
+
+// inUpdateRequest reports whether clients may set the column when updating a record
+func (c Column) inUpdateRequest() bool {
+	return !c.isMeta() && !c.IsPrimaryKey && !c.IsGenerated && !c.IsReadOnly
+}
+
+// inResponse reports whether the column is returned to clients
+func (c Column) inResponse() bool {
+	return !c.isMeta() && !c.IsWriteOnly
 }
 
 // parseSQLSchema parses SQL CREATE TABLE statements and extracts table information
@@ -37,6 +88,7 @@ func parseSQLSchema(filename string) ([]Table, error) {
 	var tables []Table
 	var currentTable *Table
 	var inTableDefinition bool
+	enums := map[string][]string{}
 
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
@@ -47,6 +99,13 @@ func parseSQLSchema(filename string) ([]Table, error) {
 			continue
 		}
 
+		// Collect enum types so columns using them can be validated
+		enumRegex := regexp.MustCompile(`(?i)CREATE\s+TYPE\s+(\w+)\s+AS\s+ENUM\s*\((.*)\)`)
+		if matches := enumRegex.FindStringSubmatch(line); matches != nil {
+			enums[strings.ToLower(matches[1])] = parseValueList(matches[2])
+			continue
+		}
+
 		// Detect CREATE TABLE statement
 		createTableRegex := regexp.MustCompile(`(?i)CREATE\s+TABLE\s+(\w+)\s*\(`)
 		if matches := createTableRegex.FindStringSubmatch(line); matches != nil {
@@ -71,6 +130,11 @@ func parseSQLSchema(filename string) ([]Table, error) {
 
 		// Parse column definitions
 		if inTableDefinition && currentTable != nil {
+			if isTableConstraint(line) {
+				constraint := strings.TrimSuffix(stripComment(line), ",")
+				currentTable.Constraints = append(currentTable.Constraints, constraint)
+				continue
+			}
 			column := parseColumnDefinition(line)
 			if column != nil {
 				currentTable.Columns = append(currentTable.Columns, *column)
@@ -85,8 +149,20 @@ func parseSQLSchema(filename string) ([]Table, error) {
 	// Generate Go field information for each column
 	for i := range tables {
 		for j := range tables[i].Columns {
-			generateGoFieldInfo(&tables[i].Columns[j])
+			col := &tables[i].Columns[j]
+			col.EnumValues = enums[strings.ToLower(col.Type)]
+			mentions := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(col.Name) + `\b`)
+			for _, constraint := range tables[i].Constraints {
+				if expr := extractCheck(constraint); expr != "" && mentions.MatchString(expr) {
+					col.Checks = append(col.Checks, expr)
+				}
+				if matches := foreignKeyConstraint.FindStringSubmatch(constraint); matches != nil && strings.EqualFold(matches[1], col.Name) {
+					col.RefTable, col.RefColumn = referencedColumn(matches[2], matches[3])
+				}
+			}
+			generateGoFieldInfo(col)
 		}
+		assignFieldNames(&tables[i])
 	}
 
 	return tables, nil
@@ -95,16 +171,14 @@ func parseSQLSchema(filename string) ([]Table, error) {
 // parseColumnDefinition parses a single column definition line
 func parseColumnDefinition(line string) *Column {
 	line = strings.TrimSpace(line)
-	line = strings.TrimSuffix(line, ",")
 
-	// Skip constraint definitions
-	constraintKeywords := []string{"CONSTRAINT", "PRIMARY KEY", "FOREIGN KEY", "UNIQUE", "CHECK", "INDEX"}
-	lineUpper := strings.ToUpper(line)
-	for _, keyword := range constraintKeywords {
-		if strings.Contains(lineUpper, keyword) {
-			return nil
-		}
+	// Split off inline comment annotations such as "-- @readonly"
+	annotations := ""
+	if idx := strings.Index(line, "--"); idx >= 0 {
+		annotations = line[idx+2:]
+		line = strings.TrimSpace(line[:idx])
 	}
+	line = strings.TrimSuffix(line, ",")
 
 	parts := strings.Fields(line)
 	if len(parts) < 2 {
@@ -131,6 +205,29 @@ func parseColumnDefinition(line string) *Column {
 	if strings.Contains(fullLine, "NOT NULL") {
 		column.IsNullable = false
 	}
+	if regexp.MustCompile(`\bUNIQUE\b`).MatchString(fullLine) {
+		column.IsUnique = true
+	}
+	if matches := regexp.MustCompile(`^(?:VAR)?CHAR(?:ACTER)?\((\d+)\)`).FindStringSubmatch(strings.ToUpper(columnType)); matches != nil {
+		column.Length, _ = strconv.Atoi(matches[1])
+	}
+	if expr := extractCheck(line); expr != "" {
+		column.InlineCheck = expr
+		column.Checks = append(column.Checks, expr)
+		line = strings.Replace(line, checkClause(line), "", 1)
+	}
+
+	if matches := referencesClause.FindStringSubmatch(line); matches != nil {
+		column.RefTable, column.RefColumn = referencedColumn(matches[1], matches[2])
+	}
+
+	// Extract generated and identity clauses before looking for DEFAULT
+	generatedRegex := regexp.MustCompile(`(?i)GENERATED\s+(ALWAYS|BY\s+DEFAULT)\s+AS\s+(IDENTITY(\s*\(.*\))?|\(.*\)\s*STORED)`)
+	if match := generatedRegex.FindString(line); match != "" {
+		column.GeneratedExpr = match
+		column.IsGenerated = true
+		line = strings.Replace(line, match, "", 1)
+	}
 
 	// Extract default value
 	defaultRegex := regexp.MustCompile(`(?i)DEFAULT\s+([^,\s]+)`)
@@ -138,9 +235,100 @@ func parseColumnDefinition(line string) *Column {
 		column.DefaultValue = matches[1]
 	}
 
+	// Apply column annotations
+	for _, match := range regexp.MustCompile(`@(\w+)`).FindAllStringSubmatch(annotations, -1) {
+		switch strings.ToLower(match[1]) {
+		case "readonly":
+			column.IsReadOnly = true
+		case "writeonly":
+			column.IsWriteOnly = true
+		}
+	}
+
 	return column
 }
 
+// referencesClause matches the inline REFERENCES clause of a foreign key column
+var referencesClause = regexp.MustCompile(`(?i)\bREFERENCES\s+(?:\w+\.)?"?(\w+)"?\s*(?:\(\s*"?(\w+)"?\s*\))?`)
+
+// foreignKeyConstraint matches a table-level FOREIGN KEY constraint on a single column
+var foreignKeyConstraint = regexp.MustCompile(`(?i)\bFOREIGN\s+KEY\s*\(\s*"?(\w+)"?\s*\)\s*REFERENCES\s+(?:\w+\.)?"?(\w+)"?\s*(?:\(\s*"?(\w+)"?\s*\))?`)
+
+// referencedColumn returns the lowercase table and column a foreign key references. A reference
+// without a column list targets the primary key, which is id in this schema.
+func referencedColumn(table, column string) (string, string) {
+	if column == "" {
+		column = "id"
+	}
+	return strings.ToLower(table), strings.ToLower(column)
+}
+
+// tableConstraintKeywords start table-level constraint lines rather than column definitions
+var tableConstraintKeywords = []string{"CONSTRAINT", "PRIMARY KEY", "FOREIGN KEY", "UNIQUE", "CHECK", "EXCLUDE", "INDEX"}
+
+// isTableConstraint reports whether a line inside CREATE TABLE defines a constraint instead of a column
+func isTableConstraint(line string) bool {
+	lineUpper := strings.ToUpper(line)
+	for _, keyword := range tableConstraintKeywords {
+		if strings.HasPrefix(lineUpper, keyword+" ") || strings.HasPrefix(lineUpper, keyword+"(") {
+			return true
+		}
+	}
+	return false
+}
+
+// stripComment removes a trailing "--" comment from a line
+func stripComment(line string) string {
+	if idx := strings.Index(line, "--"); idx >= 0 {
+		line = line[:idx]
+	}
+	return strings.TrimSpace(line)
+}
+
+// checkClause returns the full "CHECK (...)" clause in line, balancing parentheses
+func checkClause(line string) string {
+	loc := regexp.MustCompile(`(?i)\bCHECK\s*\(`).FindStringIndex(line)
+	if loc == nil {
+		return ""
+	}
+	depth := 0
+	for i := loc[1] - 1; i < len(line); i++ {
+		switch line[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return line[loc[0] : i+1]
+			}
+		}
+	}
+	return ""
+}
+
+// extractCheck returns the expression inside the CHECK clause of line, or "" if there is none
+func extractCheck(line string) string {
+	clause := checkClause(line)
+	if clause == "" {
+		return ""
+	}
+	open := strings.Index(clause, "(")
+	return strings.TrimSpace(clause[open+1 : len(clause)-1])
+}
+
+// parseValueList splits "'a', 'b'" into its unquoted values
+func parseValueList(list string) []string {
+	var values []string
+	for _, value := range strings.Split(list, ",") {
+		value = strings.TrimSpace(value)
+		value = strings.TrimSuffix(strings.TrimPrefix(value, "'"), "'")
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 // generateGoFieldInfo generates Go field information based on SQL column type
 func generateGoFieldInfo(column *Column) {
 	// Map SQL types to Go types
@@ -186,6 +374,14 @@ func generateGoFieldInfo(column *Column) {
 		gormParts = append(gormParts, "not null")
 	}
 
+	// Let the database fill generated columns, and keep read-only columns out of updates. Defaulted
+	// columns are only left to the database when the create request leaves them out.
+	if column.IsGenerated {
+		gormParts = append(gormParts, "->")
+	} else if column.IsReadOnly {
+		gormParts = append(gormParts, "<-:create")
+	}
+
 	// Add type specification for JSONB fields
 	sqlTypeUpper := strings.ToUpper(column.Type)
 	if strings.Contains(sqlTypeUpper, "JSONB") {
@@ -200,22 +396,6 @@ func generateGoFieldInfo(column *Column) {
 	column.JSONTag = fmt.Sprintf(`json:"%s,omitempty"`, column.Name)
 }
 
-// toCamelCase converts snake_case to CamelCase with proper ID handling
-func toCamelCase(s string) string {
-	parts := strings.Split(strings.ToLower(s), "_")
-	for i := range parts {
-		if len(parts[i]) > 0 {
-			// Handle common ID patterns
-			if parts[i] == "id" {
-				parts[i] = "ID"
-			} else {
-				parts[i] = strings.ToUpper(string(parts[i][0])) + parts[i][1:]
-			}
-		}
-	}
-	return strings.Join(parts, "")
-}
-
 // toSnakeCase converts CamelCase to snake_case
 func toSnakeCase(s string) string {
 	var result strings.Builder
@@ -0,0 +1,77 @@
+package main
+
+import "testing"
+
+// parsedColumn parses a column definition the way parseSQLSchema does
+func parsedColumn(t *testing.T, line string) Column {
+	t.Helper()
+	col := parseColumnDefinition(line)
+	if col == nil {
+		t.Fatalf("parseColumnDefinition(%q) = nil", line)
+	}
+	generateGoFieldInfo(col)
+	return *col
+}
+
+func TestColumnRequests(t *testing.T) {
+	withConfig(t, Config{})
+
+	tests := []struct {
+		line                     string
+		create, update, response bool
+	}{
+		{"id BIGSERIAL PRIMARY KEY", false, false, false},
+		{"created_at TIMESTAMP NOT NULL DEFAULT NOW()", false, false, false},
+		{"quantity INTEGER NOT NULL", true, true, true},
+		{"status VARCHAR(20) DEFAULT 'pending'", true, true, true},
+		{"total NUMERIC(12,2) GENERATED ALWAYS AS (quantity * unit_price) STORED", false, false, true},
+		{"seq BIGINT GENERATED ALWAYS AS IDENTITY", false, false, true},
+		{"external_ref TEXT,  -- @readonly", true, false, true},
+		{"secret_token TEXT   -- @writeonly", true, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			col := parsedColumn(t, tt.line)
+			if got := col.inCreateRequest(); got != tt.create {
+				t.Errorf("inCreateRequest() = %v, want %v", got, tt.create)
+			}
+			if got := col.inUpdateRequest(); got != tt.update {
+				t.Errorf("inUpdateRequest() = %v, want %v", got, tt.update)
+			}
+			if got := col.inResponse(); got != tt.response {
+				t.Errorf("inResponse() = %v, want %v", got, tt.response)
+			}
+		})
+	}
+}
+
+func TestGormTagLeavesDefaultsToRequests(t *testing.T) {
+	withConfig(t, Config{})
+
+	col := parsedColumn(t, "active BOOLEAN NOT NULL DEFAULT TRUE")
+	if want := `gorm:"column:active;not null"`; col.GormTag != want {
+		t.Errorf("GormTag = %s, want %s", col.GormTag, want)
+	}
+}
+
+func TestMarkTenantColumns(t *testing.T) {
+	withConfig(t, Config{Tenancy: &TenancyConfig{SharedTables: []string{"plans"}}})
+
+	tables := []Table{
+		{Name: "orders", Columns: []Column{parsedColumn(t, "tenant_id TEXT NOT NULL"), parsedColumn(t, "quantity INTEGER NOT NULL")}},
+		{Name: "plans", Columns: []Column{parsedColumn(t, "tenant_id TEXT NOT NULL")}},
+	}
+	markTenantColumns(tables)
+
+	tenant := tables[0].Columns[0]
+	if tenant.inCreateRequest() || tenant.inUpdateRequest() || !tenant.inResponse() {
+		t.Errorf("tenant column create/update/response = %v/%v/%v, want false/false/true",
+			tenant.inCreateRequest(), tenant.inUpdateRequest(), tenant.inResponse())
+	}
+	if !tables[0].Columns[1].inCreateRequest() {
+		t.Error("quantity left the create request")
+	}
+	if shared := tables[1].Columns[0]; !shared.inCreateRequest() || !shared.inUpdateRequest() {
+		t.Error("tenant_id of a shared table left the requests")
+	}
+}
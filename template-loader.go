@@ -16,32 +16,174 @@ func getTemplateDirectory(templateName string) string {
 		"application-interface":         "application",
 		"application-interfaces":        "application",
 		"application-service":           "application",
+		"application-service-mutations": "application",
+		"application-service-audited":   "application",
+		"application-soft-delete":       "application",
+		"application-soft-delete-audit": "application",
+		"application-audit":             "application",
+		"application-audit-test":        "application",
+		"application-batch":             "application",
+		"application-batch-methods":     "application",
+		"application-batch-test":        "application",
+		"dto-audit":                     "application",
 		"dto":                           "application",
+		"dto-patch":                     "application",
+		"dto-patch-test":                "application",
+		"dto-test-entity":               "application",
+		"dto-validation":                "application",
+		"dto-validation-test":           "application",
+		"dto-batch":                     "application",
 
 		// Interactor layer
 		"interactor-adapter":           "interactor",
+		"interactor-adapter-history":   "interactor",
+		"interactor-adapter-restore":   "interactor",
 		"interactor-interface-content": "interactor",
 		"interactor-interfaces":        "interactor",
 		"interactor-service":           "interactor",
 
 		// Domain layer
-		"domain-model": "domain",
-		"meta-field":   "domain",
+		"domain-model":      "domain",
+		"meta-field":        "domain",
+		"query-model":       "domain",
+		"query-model-test":  "domain",
+		"domain-errors":     "domain",
+		"api-key-model":     "domain",
+		"audit-field":       "domain",
+		"audit-entry-model": "domain",
+		"response":          "response",
+
+		// Tenancy
+		"tenancy":      "tenancy",
+		"tenancy-test": "tenancy",
+
+		// Authentication
+		"auth-principal":    "auth",
+		"auth-jwt":          "auth",
+		"auth-keys":         "auth",
+		"auth-jwt-test":     "auth",
+		"auth-api-key":      "auth",
+		"auth-api-key-test": "auth",
 
 		// Repository layer
-		"postgres-repository": "repository",
+		"postgres-repository":           "repository",
+		"postgres-query":                "repository",
+		"postgres-query-test":           "repository",
+		"postgres-errors":               "repository",
+		"postgres-errors-test":          "repository",
+		"postgres-conn-tenant-test":     "repository",
+		"postgres-conn-test":            "repository",
+		"postgres-transaction-test":     "repository",
+		"postgres-access":               "repository",
+		"postgres-access-owner":         "repository",
+		"postgres-audit-log-repository": "repository",
+		"postgres-transaction":          "repository",
+		"postgres-access-tenant":        "repository",
+		"postgres-conn":                 "repository",
+		"postgres-conn-tenant":          "repository",
+		"postgres-soft-delete":          "repository",
+		"postgres-version":              "repository",
+		"postgres-batch":                "repository",
+		"postgres-api-key-repository":   "repository",
 
 		// REST layer
-		"rest-api-main":         "rest",
-		"rest-func-create":      "rest",
-		"rest-func-delete":      "rest",
-		"rest-func-get-all":     "rest",
-		"rest-func-get-by-id":   "rest",
-		"rest-func-update":      "rest",
-		"rest-handler-header":   "rest",
-		"rest-parameter-header": "rest",
-		"rest-parameter":        "rest",
-		"rest-routes":           "rest",
+		"rest-api-main":            "rest",
+		"rest-func-create":         "rest",
+		"rest-func-delete":         "rest",
+		"rest-func-get-all":        "rest",
+		"rest-func-get-all-cursor": "rest",
+		"rest-func-get-by-id":      "rest",
+		"rest-func-update":         "rest",
+		"rest-func-patch":          "rest",
+		"rest-handler-header":      "rest",
+		"rest-parameter-header":    "rest",
+		"rest-parameter":           "rest",
+		"rest-query":               "rest",
+		"rest-query-test":          "rest",
+		"rest-errors":              "rest",
+		"rest-errors-test":         "rest",
+		"rest-context-test":        "rest",
+		"rest-response-wrapper":    "rest",
+		"rest-response-problem":    "rest",
+		"rest-routes":              "rest",
+		"rest-auth-test":           "rest",
+		"rest-api-key":             "rest",
+		"rest-tenant":              "rest",
+		"rest-tenant-test":         "rest",
+		"rest-func-history":        "rest",
+		"rest-routes-history":      "rest",
+		"rest-func-restore":        "rest",
+		"rest-routes-restore":      "rest",
+		"rest-deleted":             "rest",
+		"rest-deleted-test":        "rest",
+		"rest-version":             "rest",
+		"rest-version-test":        "rest",
+		"rest-batch":               "rest",
+		"rest-batch-test":          "rest",
+		"rest-func-batch":          "rest",
+		"rest-routes-batch":        "rest",
+		"rest-transfer":            "rest",
+		"rest-transfer-test":       "rest",
+		"rest-func-transfer":       "rest",
+		"rest-openapi":             "rest",
+		"rest-openapi-test":        "rest",
+		"rest-docs":                "rest",
+
+		// Client SDK
+		"client":                  "client",
+		"client-envelope-wrapper": "client",
+		"client-envelope-problem": "client",
+		"client-entity":           "client",
+		"client-test":             "client",
+		"client-test-entity":      "client",
+		"ts-http":                 "client",
+		"ts-envelope-wrapper":     "client",
+		"ts-envelope-problem":     "client",
+		"ts-client":               "client",
+		"ts-client-entity":        "client",
+		"ts-index":                "client",
+		"ts-package":              "client",
+		"ts-tsconfig":             "client",
+
+		// Fake services of the API tests
+		"testsupport":        "testsupport",
+		"testsupport-entity": "testsupport",
+
+		// gRPC layer
+		"grpc-pb":                 "grpc",
+		"grpc-pb-message":         "grpc",
+		"grpc-pb-service":         "grpc",
+		"grpc-errors":             "grpc",
+		"grpc-errors-test":        "grpc",
+		"grpc-query":              "grpc",
+		"grpc-mapper":             "grpc",
+		"grpc-mapper-entity":      "grpc",
+		"grpc-server":             "grpc",
+		"grpc-server-entity":      "grpc",
+		"grpc-server-list":        "grpc",
+		"grpc-server-list-cursor": "grpc",
+		"grpc-tenant":             "grpc",
+		"grpc-server-test":        "grpc",
+		"grpc-server-test-entity": "grpc",
+
+		// GraphQL layer
+		"graphql-handler":         "graphql",
+		"graphql-tenant":          "graphql",
+		"graphql-errors":          "graphql",
+		"graphql-errors-test":     "graphql",
+		"graphql-query":           "graphql",
+		"graphql-loader":          "graphql",
+		"graphql-resolver":        "graphql",
+		"graphql-fetch-by-id":     "graphql",
+		"graphql-fetch-by-key":    "graphql",
+		"graphql-resolver-entity": "graphql",
+		"graphql-list":            "graphql",
+		"graphql-list-cursor":     "graphql",
+		"graphql-relation-one":    "graphql",
+		"graphql-relation-many":   "graphql",
+		"graphql-test":            "graphql",
+		"graphql-test-entity":     "graphql",
+		"graphql-test-relation":   "graphql",
 
 		// Base templates
 		"go-mod":            "base",
@@ -50,9 +192,12 @@ func getTemplateDirectory(templateName string) string {
 		"readme":            "base",
 		"config":            "base",
 		"db-connection":     "base",
+		"api-key-cli":       "base",
 
 		// Migration templates
 		"goose-migration": "migration",
+		"goose-api-keys":  "migration",
+		"goose-audit-log": "migration",
 
 		// Docker templates
 		"dockerfile":                  "docker",
@@ -106,3 +251,12 @@ func processTemplate(templateName string, variables map[string]string) (string,
 
 	return replaceTemplateVariables(template, variables), nil
 }
+
+// mustProcessTemplate is processTemplate for generators that cannot recover from a broken template
+func mustProcessTemplate(templateName string, variables map[string]string) string {
+	content, err := processTemplate(templateName, variables)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to process %s template: %v", templateName, err))
+	}
+	return content
+}
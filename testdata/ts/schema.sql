@@ -0,0 +1,27 @@
+CREATE TYPE order_status AS ENUM ('pending', 'paid', 'shipped');
+
+CREATE TABLE customers (
+    id BIGSERIAL PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    api_token TEXT,  -- @writeonly
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE orders (
+    id BIGSERIAL PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL,
+    customer_id BIGINT NOT NULL REFERENCES customers(id),
+    status order_status NOT NULL DEFAULT 'pending',
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    unit_price NUMERIC(10,2) NOT NULL,
+    total NUMERIC(12,2) GENERATED ALWAYS AS (quantity * unit_price) STORED,
+    gift BOOLEAN,
+    tags JSONB,
+    shipped_at TIMESTAMPTZ,
+    external_ref TEXT,  -- @readonly
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// generateTestSupport creates internal/testsupport: the fake services the tests of the REST client,
+// the gRPC API and the GraphQL endpoint run against
+func generateTestSupport(moduleName string, tables []Table) error {
+	var fakeServices, serviceFields, serviceInit strings.Builder
+	for _, table := range tables {
+		names := namesFor(table)
+		fakeServices.WriteString(mustProcessTemplate("testsupport-entity", map[string]string{
+			"struct_name":   names.Struct,
+			"plural_name":   names.Plural,
+			"entity_plural": names.EntityPlural,
+		}))
+		serviceFields.WriteString(fmt.Sprintf("\t%s *Fake%sService\n", names.Struct, names.Struct))
+		serviceInit.WriteString(fmt.Sprintf("\t\t%s: &Fake%sService{Store: NewStore[dto.%sResponse](%q)},\n", names.Struct, names.Struct, names.Struct, names.Entity))
+	}
+
+	filePath := filepath.Join(moduleName, "internal", "testsupport", "fakes.go")
+	content := mustProcessTemplate("testsupport", map[string]string{
+		"module_name":    moduleName,
+		"fake_services":  fakeServices.String(),
+		"service_fields": serviceFields.String(),
+		"service_init":   serviceInit.String(),
+	})
+	if err := writeFile(filePath, content); err != nil {
+		return err
+	}
+	fmt.Printf("Created test support: %s\n", filePath)
+	return nil
+}
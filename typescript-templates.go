@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// tsIdentifier matches member names that TypeScript accepts unquoted
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsMember writes a member of a TypeScript interface, optional unless required
+func tsMember(name, tsType string, required bool) string {
+	if !tsIdentifier.MatchString(name) {
+		name = strconv.Quote(name)
+	}
+	if !required {
+		name += "?"
+	}
+	return fmt.Sprintf("  %s: %s;\n", name, tsType)
+}
+
+// tsEnumName names the union type of a column whose values are limited to a list, e.g. UserStatus
+func tsEnumName(table Table, col Column) string {
+	return namesFor(table).Struct + col.FieldName
+}
+
+// tsEnumValues returns the TypeScript literals of the values a column is limited to, if any, as its
+// validation rules list them
+func tsEnumValues(col Column) []string {
+	numeric := col.GoType == "int64" || col.GoType == "float64"
+	for _, rule := range col.validationRules() {
+		param, found := strings.CutPrefix(rule, "oneof=")
+		if !found {
+			continue
+		}
+		var values []string
+		for _, value := range strings.Fields(param) {
+			if _, err := strconv.ParseFloat(value, 64); numeric && err == nil {
+				values = append(values, value)
+			} else {
+				values = append(values, strconv.Quote(value))
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// tsType returns the TypeScript type of a column in the JSON of the DTOs
+func tsType(table Table, col Column) string {
+	if tsEnumValues(col) != nil {
+		return tsEnumName(table, col)
+	}
+	switch col.GoType {
+	case "int64", "float64":
+		return "number"
+	case "bool":
+		return "boolean"
+	case "[]string":
+		return "string[]"
+	default:
+		// time.Time is sent as an RFC 3339 string
+		return "string"
+	}
+}
+
+// generateTSModels renders the TypeScript types of the DTOs of every table, member for member as generateDTO emits them
+func generateTSModels(moduleName string, tables []Table) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("// Generated by boGO from the schema of %s. Regenerate the service instead of editing this file.\n", moduleName))
+	b.WriteString("// Numbers hold int64 and float64 columns, and timestamps are RFC 3339 strings.\n")
+
+	for _, table := range tables {
+		names := namesFor(table)
+
+		var response, create, update, patch strings.Builder
+		response.WriteString(tsMember("id", "number", true))
+		for _, col := range table.Columns {
+			if col.isMeta() {
+				continue
+			}
+			member := strings.ToLower(col.Name)
+			colType := tsType(table, col)
+
+			if values := tsEnumValues(col); values != nil {
+				b.WriteString(fmt.Sprintf("\n/** Values of %s.%s */\nexport const %sValues = [%s] as const;\nexport type %s = (typeof %sValues)[number];\n",
+					table.Name, col.Name, tsEnumName(table, col), strings.Join(values, ", "), tsEnumName(table, col), tsEnumName(table, col)))
+			}
+			if col.inResponse() {
+				response.WriteString(tsMember(member, colType, true))
+			}
+			if col.inCreateRequest() {
+				create.WriteString(tsMember(member, colType, col.requiresValue()))
+			}
+			if col.inUpdateRequest() {
+				update.WriteString(tsMember(member, colType, col.requiredOnUpdate()))
+				if col.IsNullable {
+					patch.WriteString(tsMember(member, colType+" | null", false))
+				} else {
+					patch.WriteString(tsMember(member, colType, false))
+				}
+			}
+		}
+		response.WriteString(tsMember("created_at", "string", true))
+		response.WriteString(tsMember("updated_at", "string", true))
+		if usesVersioning(table) {
+			response.WriteString(tsMember("version", "number", true))
+		}
+		if usesSoftDelete(table) {
+			response.WriteString(tsMember("deleted_at", "string | null", true))
+		}
+		if usesAuditColumns() {
+			response.WriteString(tsMember("created_by", "string", true))
+			response.WriteString(tsMember("updated_by", "string", true))
+		}
+
+		columns := queryColumnsFor(table)
+		quoted := make([]string, len(columns))
+		for i, col := range columns {
+			quoted[i] = strconv.Quote(col.Name)
+		}
+
+		b.WriteString(fmt.Sprintf("\n/** A %s, as the API returns it */\nexport interface %sResponse {\n%s}\n", names.Entity, names.Struct, response.String()))
+		b.WriteString(fmt.Sprintf("\n/** The body of a request creating a %s */\nexport interface Create%sRequest {\n%s}\n", names.Entity, names.Struct, create.String()))
+		b.WriteString(fmt.Sprintf("\n/** The body of a request replacing a %s */\nexport interface Update%sRequest {\n%s}\n", names.Entity, names.Struct, update.String()))
+		b.WriteString(fmt.Sprintf("\n/** A merge patch of a %s: the listed members change, and null clears a nullable one */\nexport interface %sPatch {\n%s}\n", names.Entity, names.Struct, patch.String()))
+		b.WriteString(fmt.Sprintf("\n/** Columns %s can be filtered and sorted by */\nexport type %sColumn = %s;\n", names.EntityPlural, names.Struct, strings.Join(quoted, " | ")))
+	}
+	return b.String()
+}
+
+// generateTSClient renders the Client class with the CRUD methods of every table
+func generateTSClient(moduleName string, tables []Table) string {
+	var methods, modelTypes strings.Builder
+	httpTypes := []string{"ListOptions", "RequestOptions"}
+	for _, table := range tables {
+		names := namesFor(table)
+		pageType, listFunc := "Page", "listPage"
+		if usesCursorPagination(table) {
+			pageType, listFunc = "CursorPage", "listCursor"
+		}
+		changeOptions := "RequestOptions"
+		if usesVersioning(table) {
+			changeOptions = "ChangeOptions"
+		}
+		httpTypes = append(httpTypes, pageType, changeOptions)
+
+		methods.WriteString(mustProcessTemplate("ts-client-entity", map[string]string{
+			"struct_name":    names.Struct,
+			"plural_name":    names.Plural,
+			"entity_name":    names.Entity,
+			"entity_plural":  names.EntityPlural,
+			"page_type":      pageType,
+			"list_func":      listFunc,
+			"change_options": changeOptions,
+		}))
+		modelTypes.WriteString(fmt.Sprintf("  %[1]sColumn,\n  %[1]sResponse,\n  Create%[1]sRequest,\n  Update%[1]sRequest,\n  %[1]sPatch,\n", names.Struct))
+	}
+	slices.Sort(httpTypes)
+
+	return mustProcessTemplate("ts-client", map[string]string{
+		"module_name": moduleName,
+		"http_types":  strings.Join(slices.Compact(httpTypes), ", "),
+		"model_types": modelTypes.String(),
+		"methods":     methods.String(),
+	})
+}
+
+// generateTSHTTP renders the request plumbing of the client, typed with the configured response envelope
+func generateTSHTTP(moduleName string) string {
+	envelopeTemplate := "ts-envelope-wrapper"
+	if usesProblemDetails() {
+		envelopeTemplate = "ts-envelope-problem"
+	}
+
+	var clientOptions, clientHeaders string
+	if usesAPIKeys() {
+		clientOptions += "\n  /** Authenticates every request with an API key instead of a bearer token */\n  apiKey?: string;"
+		clientHeaders += "\n    if (this.options.apiKey) headers[\"X-API-Key\"] = this.options.apiKey;"
+	}
+	if usesTenancy() && tenantSource() == tenantSourceHeader {
+		clientOptions += "\n  /** Scopes every request to a tenant */\n  tenant?: string;"
+		clientHeaders += fmt.Sprintf("\n    if (this.options.tenant) headers[%q] = this.options.tenant;", tenantHeader())
+	}
+
+	return mustProcessTemplate("ts-http", map[string]string{
+		"module_name":    moduleName,
+		"envelope":       mustProcessTemplate(envelopeTemplate, map[string]string{}),
+		"client_options": clientOptions,
+		"client_headers": clientHeaders,
+	})
+}
+
+// generateTSClientPackage creates the TypeScript client of the REST API in clients/ts. It is rewritten on
+// every run, so it follows the DTOs and routes of the schema.
+func generateTSClientPackage(moduleName string, tables []Table) error {
+	clientDir := filepath.Join(moduleName, "clients", "ts")
+	vars := map[string]string{"module_name": moduleName}
+	files := map[string]string{
+		filepath.Join(clientDir, "package.json"):     mustProcessTemplate("ts-package", vars),
+		filepath.Join(clientDir, "tsconfig.json"):    mustProcessTemplate("ts-tsconfig", vars),
+		filepath.Join(clientDir, "src", "index.ts"):  mustProcessTemplate("ts-index", vars),
+		filepath.Join(clientDir, "src", "http.ts"):   generateTSHTTP(moduleName),
+		filepath.Join(clientDir, "src", "models.ts"): generateTSModels(moduleName, tables),
+		filepath.Join(clientDir, "src", "client.ts"): generateTSClient(moduleName, tables),
+	}
+
+	for filePath, content := range files {
+		if err := writeFile(filePath, content); err != nil {
+			return err
+		}
+		fmt.Printf("Created TypeScript client: %s\n", filePath)
+	}
+	return nil
+}
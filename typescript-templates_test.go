@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// TestTSClientGolden renders the TypeScript client of testdata/ts and compares it with the reviewed
+// output in its .golden files. Run go test -update after intended changes and review the diff.
+func TestTSClientGolden(t *testing.T) {
+	tables := loadTSFixture(t)
+	for name, content := range map[string]string{
+		"models.ts": generateTSModels("shop", tables),
+		"client.ts": generateTSClient("shop", tables),
+		"http.ts":   generateTSHTTP("shop"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			golden := filepath.Join("testdata", "ts", name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if content != string(want) {
+				t.Errorf("%s differs from %s; run go test -update and review the diff", name, golden)
+			}
+		})
+	}
+}
+
+// TestTSClientCompiles type-checks the generated client package of testdata/ts with the strict
+// tsconfig it ships with. It needs tsc on the PATH, e.g. npm install -g typescript.
+func TestTSClientCompiles(t *testing.T) {
+	tsc, err := exec.LookPath("tsc")
+	if err != nil {
+		t.Skip("tsc not installed")
+	}
+	tables := loadTSFixture(t)
+	generatedFiles = map[string]bool{}
+	t.Cleanup(func() { generatedFiles = map[string]bool{} })
+
+	dir := filepath.Join(t.TempDir(), "shop")
+	if err := generateTSClientPackage(dir, tables); err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command(tsc, "--noEmit", "-p", filepath.Join(dir, "clients", "ts")).CombinedOutput()
+	if err != nil {
+		t.Errorf("tsc: %v\n%s", err, out)
+	}
+}
+
+// loadTSFixture loads the config and schema of testdata/ts the way a generator run does
+func loadTSFixture(t *testing.T) []Table {
+	t.Helper()
+	cfg, err := loadConfig(filepath.Join("testdata", "ts", "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	withConfig(t, cfg)
+	tables, err := parseSQLSchema(filepath.Join("testdata", "ts", "schema.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := validateConfig(cfg, tables); err != nil {
+		t.Fatal(err)
+	}
+	markTenantColumns(tables)
+	return tables
+}
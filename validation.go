@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// checkComparisonRules maps SQL comparison operators to validator rules
+var checkComparisonRules = map[string]string{
+	">": "gt", ">=": "gte", "<": "lt", "<=": "lte", "<>": "ne", "!=": "ne", "=": "eq",
+}
+
+const sqlNumber = `(-?\d+(?:\.\d+)?)`
+
+// requiresValue reports whether clients must always send the column:
+// it is NOT NULL and the database has no default or generated value for it
+func (c Column) requiresValue() bool {
+	return !c.IsNullable && c.DefaultValue == "" && !c.IsGenerated
+}
+
+// requiredOnUpdate reports whether update requests must carry the column. An update replaces every
+// column, so a NOT NULL column needs a value even when the database has a default for it.
+func (c Column) requiredOnUpdate() bool {
+	return !c.IsNullable && !c.IsGenerated
+}
+
+// requestType returns the Go type of the column in request DTOs. Required numbers and booleans
+// are pointers, so a missing member can be told apart from an explicit zero.
+func (c Column) requestType() string {
+	return c.presenceType(c.requiresValue())
+}
+
+// updateType returns the Go type of the column in update requests
+func (c Column) updateType() string {
+	return c.presenceType(c.requiredOnUpdate())
+}
+
+// presenceType returns the Go type of the column in a request, a pointer if the request requires
+// a number or boolean
+func (c Column) presenceType(required bool) string {
+	if required && (c.GoType == "int64" || c.GoType == "float64" || c.GoType == "bool") {
+		return "*" + c.GoType
+	}
+	return c.GoType
+}
+
+// createType returns the Go type of the column in create requests. Columns with a database default
+// are pointers as well, so leaving them out keeps the default.
+func (c Column) createType() string {
+	if c.DefaultValue != "" && !strings.HasPrefix(c.GoType, "[]") {
+		return "*" + c.GoType
+	}
+	return c.requestType()
+}
+
+// requestRules returns the full validator rule list of the column in create requests
+func (c Column) requestRules() []string {
+	return c.presenceRules(c.requiresValue())
+}
+
+// updateRules returns the full validator rule list of the column in update requests
+func (c Column) updateRules() []string {
+	return c.presenceRules(c.requiredOnUpdate())
+}
+
+// presenceRules prefixes the schema rules of the column with required or omitempty
+func (c Column) presenceRules(required bool) []string {
+	rules := c.validationRules()
+	if required {
+		return append([]string{"required"}, rules...)
+	}
+	if len(rules) > 0 {
+		return append([]string{"omitempty"}, rules...)
+	}
+	return nil
+}
+
+// validationRules translates the schema constraints of the column into go-playground/validator rules.
+// Presence rules (required, omitempty) are left to the caller.
+func (c Column) validationRules() []string {
+	var rules []string
+	name := strings.ToLower(c.Name)
+
+	if c.GoType == "string" {
+		if c.Length > 0 {
+			rules = append(rules, fmt.Sprintf("max=%d", c.Length))
+		}
+		switch {
+		case name == "email" || strings.HasSuffix(name, "_email"):
+			rules = append(rules, "email")
+		case name == "url" || strings.HasSuffix(name, "_url"):
+			rules = append(rules, "url")
+		case strings.EqualFold(c.Type, "UUID"):
+			rules = append(rules, "uuid")
+		}
+	}
+
+	if rule := oneOfRule(c.EnumValues); rule != "" {
+		rules = append(rules, rule)
+	}
+
+	for _, expr := range c.Checks {
+		rules = append(rules, c.checkRules(expr)...)
+	}
+	return rules
+}
+
+// checkRules translates a CHECK expression on the column into validator rules. Only conjunctions of
+// simple comparisons, BETWEEN, IN lists and length bounds are understood; anything else is left to the database.
+func (c Column) checkRules(expr string) []string {
+	expr = unwrapParens(expr)
+	if regexp.MustCompile(`(?i)\bOR\b`).MatchString(expr) {
+		return nil
+	}
+
+	column := `"?` + regexp.QuoteMeta(c.Name) + `"?`
+	numeric := c.GoType == "int64" || c.GoType == "float64"
+	var rules []string
+
+	// BETWEEN contains its own AND, so it is handled before splitting the conjunction
+	between := regexp.MustCompile(`(?i)` + column + `\s+BETWEEN\s+` + sqlNumber + `\s+AND\s+` + sqlNumber)
+	for _, match := range between.FindAllStringSubmatch(expr, -1) {
+		if numeric {
+			rules = append(rules, "gte="+match[1], "lte="+match[2])
+		}
+	}
+	expr = between.ReplaceAllString(expr, "")
+
+	comparison := regexp.MustCompile(`(?i)^` + column + `\s*(>=|<=|<>|!=|>|<|=)\s*` + sqlNumber + `$`)
+	notEmpty := regexp.MustCompile(`(?i)^` + column + `\s*(<>|!=)\s*''$`)
+	inList := regexp.MustCompile(`(?i)^` + column + `\s+IN\s*\((.*)\)$`)
+	length := regexp.MustCompile(`(?i)^(?:char_)?length\(\s*` + column + `\s*\)\s*(>=|<=|>|<)\s*(\d+)$`)
+
+	for _, part := range regexp.MustCompile(`(?i)\s+AND\s+`).Split(expr, -1) {
+		part = unwrapParens(part)
+		switch {
+		case comparison.MatchString(part) && numeric:
+			match := comparison.FindStringSubmatch(part)
+			rules = append(rules, checkComparisonRules[match[1]]+"="+match[2])
+		case notEmpty.MatchString(part) && c.GoType == "string":
+			rules = append(rules, "min=1")
+		case inList.MatchString(part):
+			if rule := oneOfRule(parseValueList(inList.FindStringSubmatch(part)[1])); rule != "" {
+				rules = append(rules, rule)
+			}
+		case length.MatchString(part) && c.GoType == "string":
+			match := length.FindStringSubmatch(part)
+			n, _ := strconv.Atoi(match[2])
+			switch match[1] {
+			case ">":
+				rules = append(rules, fmt.Sprintf("min=%d", n+1))
+			case ">=":
+				rules = append(rules, fmt.Sprintf("min=%d", n))
+			case "<":
+				rules = append(rules, fmt.Sprintf("max=%d", n-1))
+			case "<=":
+				rules = append(rules, fmt.Sprintf("max=%d", n))
+			}
+		}
+	}
+	return rules
+}
+
+// unwrapParens removes parentheses that enclose the whole expression, e.g. "((a > 0))" becomes "a > 0"
+func unwrapParens(expr string) string {
+	expr = strings.TrimSpace(expr)
+	for strings.HasPrefix(expr, "(") && checkClause("CHECK "+expr) == "CHECK "+expr {
+		expr = strings.TrimSpace(expr[1 : len(expr)-1])
+	}
+	return expr
+}
+
+// oneOfRule builds a oneof rule from enum labels, or "" when a label cannot be expressed in a struct tag
+func oneOfRule(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	for _, value := range values {
+		if strings.ContainsAny(value, " ,|'\"`") {
+			return ""
+		}
+	}
+	return "oneof=" + strings.Join(values, " ")
+}
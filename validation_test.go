@@ -0,0 +1,121 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRequestRules(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"note TEXT", nil},
+		{"name VARCHAR(100) NOT NULL", []string{"required", "max=100"}},
+		{"code CHAR(3)", []string{"omitempty", "max=3"}},
+		{"status VARCHAR(20) NOT NULL DEFAULT 'new'", []string{"omitempty", "max=20"}},
+		{"email VARCHAR(255) NOT NULL UNIQUE", []string{"required", "max=255", "email"}},
+		{"billing_email TEXT", []string{"omitempty", "email"}},
+		{"api_url TEXT", []string{"omitempty", "url"}},
+		{"ref UUID", []string{"omitempty", "uuid"}},
+		{"age INTEGER NOT NULL CHECK (age >= 18)", []string{"required", "gte=18"}},
+		{"price NUMERIC(10,2) CHECK (price > 0 AND price <= 999.99)", []string{"omitempty", "gt=0", "lte=999.99"}},
+		{"rating INTEGER CHECK (rating BETWEEN 1 AND 5)", []string{"omitempty", "gte=1", "lte=5"}},
+		{"size TEXT CHECK (size IN ('s', 'm', 'l'))", []string{"omitempty", "oneof=s m l"}},
+		{"slug TEXT CHECK (length(slug) > 2 AND char_length(slug) <= 40)", []string{"omitempty", "min=3", "max=40"}},
+		{"title TEXT NOT NULL CHECK (title <> '')", []string{"required", "min=1"}},
+		{"level INTEGER CHECK (level < 0 OR level > 10)", nil},
+		{"seq BIGINT GENERATED ALWAYS AS IDENTITY", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if got := parsedColumn(t, tt.line).requestRules(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("requestRules() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidationRulesOfEnumsAndTableChecks(t *testing.T) {
+	col := parsedColumn(t, "mood mood NOT NULL")
+	col.EnumValues = []string{"happy", "sad"}
+	col.Checks = append(col.Checks, "(mood <> 'sad' AND length(mood) < 10)")
+	if got, want := col.validationRules(), []string{"oneof=happy sad", "max=9"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("validationRules() = %q, want %q", got, want)
+	}
+
+	// Numeric comparisons are ignored on text columns, and length bounds on numbers
+	col = parsedColumn(t, "qty INTEGER")
+	col.Checks = []string{"length(qty) > 1", "qty <> 0"}
+	if got, want := col.validationRules(), []string{"ne=0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("validationRules() = %q, want %q", got, want)
+	}
+}
+
+func TestOneOfRule(t *testing.T) {
+	tests := []struct {
+		values []string
+		want   string
+	}{
+		{nil, ""},
+		{[]string{"a", "b"}, "oneof=a b"},
+		{[]string{"in stock", "sold"}, ""},
+		{[]string{"a,b"}, ""},
+		{[]string{"it's"}, ""},
+	}
+	for _, tt := range tests {
+		if got := oneOfRule(tt.values); got != tt.want {
+			t.Errorf("oneOfRule(%q) = %q, want %q", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestRequestTypes(t *testing.T) {
+	tests := []struct {
+		line                            string
+		request, createType, updateType string
+	}{
+		{"age INTEGER NOT NULL", "*int64", "*int64", "*int64"},
+		{"score NUMERIC(5,2) NOT NULL", "*float64", "*float64", "*float64"},
+		{"active BOOLEAN NOT NULL", "*bool", "*bool", "*bool"},
+		{"active BOOLEAN NOT NULL DEFAULT true", "bool", "*bool", "*bool"},
+		{"name TEXT NOT NULL", "string", "string", "string"},
+		{"age INTEGER", "int64", "int64", "int64"},
+		{"placed_at TIMESTAMP DEFAULT NOW()", "time.Time", "*time.Time", "time.Time"},
+		{"tags JSONB DEFAULT '[]'", "[]string", "[]string", "[]string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			col := parsedColumn(t, tt.line)
+			if got := col.requestType(); got != tt.request {
+				t.Errorf("requestType() = %s, want %s", got, tt.request)
+			}
+			if got := col.createType(); got != tt.createType {
+				t.Errorf("createType() = %s, want %s", got, tt.createType)
+			}
+			if got := col.updateType(); got != tt.updateType {
+				t.Errorf("updateType() = %s, want %s", got, tt.updateType)
+			}
+		})
+	}
+}
+
+func TestUpdateRules(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"note TEXT", nil},
+		{"status VARCHAR(20) NOT NULL DEFAULT 'new'", []string{"required", "max=20"}},
+		{"active BOOLEAN NOT NULL DEFAULT true", []string{"required"}},
+		{"code CHAR(3) DEFAULT 'abc'", []string{"omitempty", "max=3"}},
+		{"seq BIGINT GENERATED ALWAYS AS IDENTITY", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if got := parsedColumn(t, tt.line).updateRules(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("updateRules() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}